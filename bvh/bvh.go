@@ -0,0 +1,374 @@
+// The package bvh contains a float32 bounding volume hierarchy over vec3.Box
+// for fast ray casts and overlap queries.
+//
+// The hierarchy is built with the surface area heuristic (SAH)
+// and stored as a flat array of nodes in depth first order,
+// so the first child of an inner node directly follows it in memory.
+// Items are identified by their index in the slice of boxes
+// the hierarchy was built from.
+package bvh
+
+import (
+	"github.com/ungerik/go3d/vec3"
+)
+
+const (
+	// maxLeafSize is the number of items below which a node
+	// becomes a leaf if splitting doesn't pay off.
+	maxLeafSize = 4
+
+	// numBins is the number of bins used to evaluate SAH split candidates.
+	numBins = 16
+
+	// traversalCost is the SAH cost of visiting an inner node
+	// relative to the cost of testing an item.
+	traversalCost = 1
+)
+
+// Node is a node of the flattened hierarchy.
+type Node struct {
+	Bounds vec3.Box
+
+	// Offset is the index of the second child for inner nodes
+	// and the index of the first item in T.Items for leaves.
+	Offset int32
+
+	// Count is the number of items of a leaf and zero for inner nodes.
+	Count int32
+}
+
+// IsLeaf returns true if the node has no children.
+func (self *Node) IsLeaf() bool {
+	return self.Count > 0
+}
+
+// T is a bounding volume hierarchy.
+type T struct {
+	Nodes []Node
+
+	// Items contains the indices of the boxes in the order they are
+	// referenced by the leaves.
+	Items []int32
+
+	// Boxes contains the boxes of Items in the same order.
+	Boxes []vec3.Box
+
+	centroids []vec3.T
+}
+
+// New builds a hierarchy over boxes.
+func New(boxes []vec3.Box) *T {
+	self := &T{}
+	self.Build(boxes)
+	return self
+}
+
+// Build rebuilds the hierarchy over boxes reusing the memory of self.
+func (self *T) Build(boxes []vec3.Box) {
+	self.Nodes = self.Nodes[:0]
+	self.Items = self.Items[:0]
+	self.Boxes = self.Boxes[:0]
+	if len(boxes) == 0 {
+		return
+	}
+	self.centroids = self.centroids[:0]
+	for i := range boxes {
+		self.Items = append(self.Items, int32(i))
+		self.centroids = append(self.centroids, boxes[i].Center())
+	}
+	b := builder{boxes: boxes, centroids: self.centroids, items: self.Items, nodes: self.Nodes}
+	b.build(0, len(boxes))
+	self.Nodes = b.nodes
+	for _, item := range self.Items {
+		self.Boxes = append(self.Boxes, boxes[item])
+	}
+}
+
+type builder struct {
+	boxes     []vec3.Box
+	centroids []vec3.T
+	items     []int32
+	nodes     []Node
+}
+
+type bin struct {
+	bounds vec3.Box
+	count  int
+}
+
+// build creates the node for items[begin:end] and returns its index.
+func (self *builder) build(begin, end int) int {
+	index := len(self.nodes)
+	self.nodes = append(self.nodes, Node{})
+
+	bounds := vec3.EmptyBox
+	centroidBounds := vec3.EmptyBox
+	for _, item := range self.items[begin:end] {
+		bounds.ExtendByBox(&self.boxes[item])
+		centroidBounds.ExtendByPoint(&self.centroids[item])
+	}
+	self.nodes[index].Bounds = bounds
+
+	count := end - begin
+	axis, split, ok := self.findSplit(begin, end, &bounds, &centroidBounds)
+	if !ok {
+		self.nodes[index].Offset = int32(begin)
+		self.nodes[index].Count = int32(count)
+		return index
+	}
+
+	mid := begin
+	for i := begin; i < end; i++ {
+		if self.binIndex(self.items[i], axis, &centroidBounds) < split {
+			self.items[i], self.items[mid] = self.items[mid], self.items[i]
+			mid++
+		}
+	}
+
+	self.build(begin, mid)
+	second := self.build(mid, end)
+	self.nodes[index].Offset = int32(second)
+	return index
+}
+
+// findSplit returns the axis and the bin index of the cheapest split
+// according to the surface area heuristic. ok is false if a leaf is cheaper.
+func (self *builder) findSplit(begin, end int, bounds, centroidBounds *vec3.Box) (axis, split int, ok bool) {
+	count := end - begin
+	if count <= 1 {
+		return 0, 0, false
+	}
+	leafCost := float32(count)
+	bestCost := leafCost
+	if count > maxLeafSize {
+		// Always split large nodes if any split is possible.
+		bestCost = vec3.MaxVal[0]
+	}
+	parentArea := bounds.SurfaceArea()
+	if parentArea == 0 {
+		parentArea = 1
+	}
+
+	for a := 0; a < 3; a++ {
+		if centroidBounds.Max[a] <= centroidBounds.Min[a] {
+			continue
+		}
+		var bins [numBins]bin
+		for i := range bins {
+			bins[i].bounds = vec3.EmptyBox
+		}
+		for _, item := range self.items[begin:end] {
+			b := &bins[self.binIndex(item, a, centroidBounds)]
+			b.bounds.ExtendByBox(&self.boxes[item])
+			b.count++
+		}
+
+		// Sweep from the right to get the area and count right of every split.
+		var rightArea [numBins]float32
+		var rightCount [numBins]int
+		right := vec3.EmptyBox
+		n := 0
+		for i := numBins - 1; i > 0; i-- {
+			right.ExtendByBox(&bins[i].bounds)
+			n += bins[i].count
+			rightArea[i] = right.SurfaceArea()
+			rightCount[i] = n
+		}
+
+		left := vec3.EmptyBox
+		n = 0
+		for i := 1; i < numBins; i++ {
+			left.ExtendByBox(&bins[i-1].bounds)
+			n += bins[i-1].count
+			if n == 0 || rightCount[i] == 0 {
+				continue
+			}
+			cost := traversalCost + (left.SurfaceArea()*float32(n)+rightArea[i]*float32(rightCount[i]))/parentArea
+			if cost < bestCost {
+				bestCost = cost
+				axis = a
+				split = i
+				ok = true
+			}
+		}
+	}
+	return axis, split, ok
+}
+
+func (self *builder) binIndex(item int32, axis int, centroidBounds *vec3.Box) int {
+	extent := centroidBounds.Max[axis] - centroidBounds.Min[axis]
+	i := int((self.centroids[item][axis] - centroidBounds.Min[axis]) / extent * numBins)
+	if i >= numBins {
+		i = numBins - 1
+	}
+	return i
+}
+
+// Bounds returns the bounding box of all items.
+func (self *T) Bounds() vec3.Box {
+	if len(self.Nodes) == 0 {
+		return vec3.EmptyBox
+	}
+	return self.Nodes[0].Bounds
+}
+
+// Refit updates the node bounds after the boxes moved without changing
+// the structure of the hierarchy. boxes must have the same length and order
+// as the boxes the hierarchy was built from. Refitting is much faster than
+// rebuilding but the query performance degrades if the boxes move a lot.
+func (self *T) Refit(boxes []vec3.Box) {
+	for i, item := range self.Items {
+		self.Boxes[i] = boxes[item]
+	}
+	// Children are always stored after their parents,
+	// so iterating backwards visits children first.
+	for i := len(self.Nodes) - 1; i >= 0; i-- {
+		node := &self.Nodes[i]
+		if node.IsLeaf() {
+			node.Bounds = vec3.EmptyBox
+			for j := node.Offset; j < node.Offset+node.Count; j++ {
+				node.Bounds.ExtendByBox(&self.Boxes[j])
+			}
+		} else {
+			node.Bounds = vec3.Join(&self.Nodes[i+1].Bounds, &self.Nodes[node.Offset].Bounds)
+		}
+	}
+}
+
+// QueryBox appends the indices of all items whose boxes
+// overlap or touch box to result and returns it.
+func (self *T) QueryBox(box *vec3.Box, result []int) []int {
+	return self.query(func(bounds *vec3.Box) bool { return bounds.Intersects(box) }, result)
+}
+
+// QuerySphere appends the indices of all items whose boxes
+// overlap or touch sphere to result and returns it.
+func (self *T) QuerySphere(sphere *vec3.Sphere, result []int) []int {
+	return self.query(sphere.IntersectsBox, result)
+}
+
+func (self *T) query(overlaps func(bounds *vec3.Box) bool, result []int) []int {
+	if len(self.Nodes) == 0 {
+		return result
+	}
+	var stackMem [64]int32
+	stack := append(stackMem[:0], 0)
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &self.Nodes[index]
+		if !overlaps(&node.Bounds) {
+			continue
+		}
+		if node.IsLeaf() {
+			for i := node.Offset; i < node.Offset+node.Count; i++ {
+				if overlaps(&self.Boxes[i]) {
+					result = append(result, int(self.Items[i]))
+				}
+			}
+		} else {
+			stack = append(stack, node.Offset, index+1)
+		}
+	}
+	return result
+}
+
+// IntersectFunc is called by the ray casts for every item
+// whose box is hit by the ray to test the actual shape of the item.
+// It returns the ray parameter of the hit.
+type IntersectFunc func(index int, ray *vec3.Ray) (t float32, hit bool)
+
+// RayCast returns the item with the nearest hit of ray
+// with a parameter t in the range 0..maxT.
+// If intersect is nil, the boxes of the items are tested instead.
+func (self *T) RayCast(ray *vec3.Ray, maxT float32, intersect IntersectFunc) (index int, t float32, hit bool) {
+	return self.rayCast(ray, maxT, intersect, false)
+}
+
+// RayCastAny returns any item hit by ray with a parameter t in the range 0..maxT.
+// It is faster than RayCast and meant for occlusion tests.
+// If intersect is nil, the boxes of the items are tested instead.
+func (self *T) RayCastAny(ray *vec3.Ray, maxT float32, intersect IntersectFunc) (index int, t float32, hit bool) {
+	return self.rayCast(ray, maxT, intersect, true)
+}
+
+func (self *T) rayCast(ray *vec3.Ray, maxT float32, intersect IntersectFunc, anyHit bool) (index int, t float32, hit bool) {
+	index = -1
+	if len(self.Nodes) == 0 {
+		return index, maxT, false
+	}
+	ooDir := vec3.T{1 / ray.Dir[0], 1 / ray.Dir[1], 1 / ray.Dir[2]}
+	t = maxT
+
+	var stackMem [64]int32
+	stack := append(stackMem[:0], 0)
+	for len(stack) > 0 {
+		nodeIndex := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &self.Nodes[nodeIndex]
+		if _, ok := slabTest(&node.Bounds, ray, &ooDir, t); !ok {
+			continue
+		}
+		if node.IsLeaf() {
+			for i := node.Offset; i < node.Offset+node.Count; i++ {
+				var itemT float32
+				var ok bool
+				if intersect != nil {
+					itemT, ok = intersect(int(self.Items[i]), ray)
+				} else {
+					itemT, ok = slabTest(&self.Boxes[i], ray, &ooDir, t)
+				}
+				if ok && itemT >= 0 && itemT <= t {
+					index = int(self.Items[i])
+					t = itemT
+					hit = true
+					if anyHit {
+						return index, t, hit
+					}
+				}
+			}
+			continue
+		}
+		// Visit the nearer child first by pushing it last.
+		first, second := nodeIndex+1, node.Offset
+		tFirst, okFirst := slabTest(&self.Nodes[first].Bounds, ray, &ooDir, t)
+		tSecond, okSecond := slabTest(&self.Nodes[second].Bounds, ray, &ooDir, t)
+		switch {
+		case okFirst && okSecond:
+			if tFirst < tSecond {
+				stack = append(stack, second, first)
+			} else {
+				stack = append(stack, first, second)
+			}
+		case okFirst:
+			stack = append(stack, first)
+		case okSecond:
+			stack = append(stack, second)
+		}
+	}
+	return index, t, hit
+}
+
+// slabTest returns the entry parameter of ray into box
+// if the ray hits box within 0..maxT.
+func slabTest(box *vec3.Box, ray *vec3.Ray, ooDir *vec3.T, maxT float32) (float32, bool) {
+	tmin := float32(0)
+	tmax := maxT
+	for i := 0; i < 3; i++ {
+		t0 := (box.Min[i] - ray.Origin[i]) * ooDir[i]
+		t1 := (box.Max[i] - ray.Origin[i]) * ooDir[i]
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		if t0 > tmin {
+			tmin = t0
+		}
+		if t1 < tmax {
+			tmax = t1
+		}
+		if tmin > tmax {
+			return tmin, false
+		}
+	}
+	return tmin, true
+}
@@ -0,0 +1,207 @@
+package bvh
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/barnex/fmath"
+	"github.com/ungerik/go3d/vec3"
+)
+
+// rayEpsilon is the tolerance of ray parameters computed in different ways.
+const rayEpsilon = 1e-3 //gend:float64 1e-9
+
+func random(r *rand.Rand, min, max float32) float32 {
+	return min + float32(r.Float64())*(max-min)
+}
+
+func randomPoint(r *rand.Rand, min, max float32) vec3.T {
+	return vec3.T{random(r, min, max), random(r, min, max), random(r, min, max)}
+}
+
+func randomBoxes(r *rand.Rand, n int) []vec3.Box {
+	boxes := make([]vec3.Box, n)
+	for i := range boxes {
+		center := randomPoint(r, 0, 100)
+		extents := randomPoint(r, 0, 2)
+		boxes[i] = vec3.Box{Min: vec3.Sub(&center, &extents), Max: vec3.Add(&center, &extents)}
+	}
+	return boxes
+}
+
+func randomRay(r *rand.Rand) vec3.Ray {
+	return vec3.Ray{Origin: randomPoint(r, -20, 120), Dir: randomPoint(r, -1, 1)}
+}
+
+// boxIntersector returns an IntersectFunc that tests the boxes like RayCast
+// does without intersect function, but with vec3.Ray.IntersectBox.
+func boxIntersector(boxes []vec3.Box) IntersectFunc {
+	return func(index int, ray *vec3.Ray) (float32, bool) {
+		tmin, _, ok := ray.IntersectBox(&boxes[index])
+		if tmin < 0 {
+			tmin = 0
+		}
+		return tmin, ok
+	}
+}
+
+func sortedEqual(a, b []int) bool {
+	sort.Ints(a)
+	sort.Ints(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func checkQueries(t *testing.T, tree *T, boxes []vec3.Box, r *rand.Rand) {
+	var result []int
+	for n := 0; n < 100; n++ {
+		center := randomPoint(r, -10, 110)
+		extents := randomPoint(r, 0, 10)
+		box := vec3.Box{Min: vec3.Sub(&center, &extents), Max: vec3.Add(&center, &extents)}
+		result = tree.QueryBox(&box, result[:0])
+		var want []int
+		for i := range boxes {
+			if boxes[i].Intersects(&box) {
+				want = append(want, i)
+			}
+		}
+		if !sortedEqual(result, want) {
+			t.Fatalf("QueryBox(%v) = %v, want %v", box, result, want)
+		}
+
+		sphere := vec3.Sphere{Center: center, Radius: random(r, 0, 10)}
+		result = tree.QuerySphere(&sphere, result[:0])
+		want = want[:0]
+		for i := range boxes {
+			if sphere.IntersectsBox(&boxes[i]) {
+				want = append(want, i)
+			}
+		}
+		if !sortedEqual(result, want) {
+			t.Fatalf("QuerySphere(%v) = %v, want %v", sphere, result, want)
+		}
+	}
+}
+
+func checkRayCasts(t *testing.T, tree *T, boxes []vec3.Box, r *rand.Rand) {
+	intersect := boxIntersector(boxes)
+	for n := 0; n < 500; n++ {
+		ray := randomRay(r)
+		maxT := random(r, 0, 200)
+
+		wantIndex, wantT, wantHit := -1, maxT, false
+		for i := range boxes {
+			if ti, ok := intersect(i, &ray); ok && ti <= wantT {
+				wantIndex, wantT, wantHit = i, ti, true
+			}
+		}
+
+		index, ti, hit := tree.RayCast(&ray, maxT, intersect)
+		if hit != wantHit || ti != wantT {
+			t.Fatalf("RayCast(%v, %f) = %d, %f, %v, want %d, %f, %v", ray, maxT, index, ti, hit, wantIndex, wantT, wantHit)
+		}
+
+		index, ti, hit = tree.RayCast(&ray, maxT, nil)
+		if hit != wantHit || fmath.Abs(ti-wantT) > rayEpsilon {
+			t.Fatalf("RayCast(%v, %f, nil) = %d, %f, %v, want %d, %f, %v", ray, maxT, index, ti, hit, wantIndex, wantT, wantHit)
+		}
+
+		index, ti, hit = tree.RayCastAny(&ray, maxT, intersect)
+		if hit != wantHit {
+			t.Fatalf("RayCastAny(%v, %f) hit = %v, want %v", ray, maxT, hit, wantHit)
+		}
+		if hit {
+			if itemT, ok := intersect(index, &ray); !ok || itemT != ti || ti > maxT {
+				t.Fatalf("RayCastAny(%v, %f) = %d, %f is no hit", ray, maxT, index, ti)
+			}
+		}
+	}
+}
+
+func TestQueries(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 5, 100, 3000} {
+		boxes := randomBoxes(r, n)
+		tree := New(boxes)
+		checkQueries(t, tree, boxes, r)
+		checkRayCasts(t, tree, boxes, r)
+
+		// Move the boxes and refit.
+		for i := range boxes {
+			offset := randomPoint(r, -5, 5)
+			boxes[i].Min.Add(&offset)
+			boxes[i].Max.Add(&offset)
+		}
+		tree.Refit(boxes)
+		checkQueries(t, tree, boxes, r)
+		checkRayCasts(t, tree, boxes, r)
+	}
+}
+
+func TestBuildReusesMemory(t *testing.T) {
+	boxes := randomBoxes(rand.New(rand.NewSource(1)), 1000)
+	tree := New(boxes)
+	allocs := testing.AllocsPerRun(10, func() {
+		tree.Build(boxes)
+	})
+	if allocs != 0 {
+		t.Errorf("Build allocated %f times, want 0", allocs)
+	}
+}
+
+const benchmarkBoxes = 100000
+
+func BenchmarkBuild(b *testing.B) {
+	boxes := randomBoxes(rand.New(rand.NewSource(1)), benchmarkBoxes)
+	tree := New(boxes)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Build(boxes)
+	}
+}
+
+func BenchmarkRayCast(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	boxes := randomBoxes(r, benchmarkBoxes)
+	tree := New(boxes)
+	rays := make([]vec3.Ray, 1024)
+	for i := range rays {
+		rays[i] = randomRay(r)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.RayCast(&rays[i%len(rays)], 1000, nil)
+	}
+}
+
+func BenchmarkQueryBox(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	boxes := randomBoxes(r, benchmarkBoxes)
+	tree := New(boxes)
+	queries := randomBoxes(r, 1024)
+	for i := range queries {
+		queries[i].Expand(3)
+	}
+	var result []int
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result = tree.QueryBox(&queries[i%len(queries)], result[:0])
+	}
+}
+
+func BenchmarkRefit(b *testing.B) {
+	boxes := randomBoxes(rand.New(rand.NewSource(1)), benchmarkBoxes)
+	tree := New(boxes)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Refit(boxes)
+	}
+}
@@ -27,24 +27,46 @@ Example:
 */
 package go3d
 
+//go:generate go run ./internal/gend
+
 // Import all sub-packages for build
 import (
+	_ "github.com/ungerik/go3d/bvh"
+	_ "github.com/ungerik/go3d/bvhd"
+	_ "github.com/ungerik/go3d/frustum"
+	_ "github.com/ungerik/go3d/frustumd"
 	_ "github.com/ungerik/go3d/generic"
 	_ "github.com/ungerik/go3d/genericd"
+	_ "github.com/ungerik/go3d/gjk"
+	_ "github.com/ungerik/go3d/gjkd"
+	_ "github.com/ungerik/go3d/hashgrid2"
+	_ "github.com/ungerik/go3d/hashgrid3"
 	_ "github.com/ungerik/go3d/hermit"
 	_ "github.com/ungerik/go3d/hermitd"
+	_ "github.com/ungerik/go3d/kdtree"
+	_ "github.com/ungerik/go3d/kdtreed"
 	_ "github.com/ungerik/go3d/mat2x2"
 	_ "github.com/ungerik/go3d/mat2x2d"
 	_ "github.com/ungerik/go3d/mat3x3"
 	_ "github.com/ungerik/go3d/mat3x3d"
 	_ "github.com/ungerik/go3d/mat4x4"
 	_ "github.com/ungerik/go3d/mat4x4d"
+	_ "github.com/ungerik/go3d/matn"
+	_ "github.com/ungerik/go3d/matnd"
+	_ "github.com/ungerik/go3d/obb"
+	_ "github.com/ungerik/go3d/obbd"
+	_ "github.com/ungerik/go3d/octree"
+	_ "github.com/ungerik/go3d/octreed"
+	_ "github.com/ungerik/go3d/quadtree"
+	_ "github.com/ungerik/go3d/quadtreed"
 	_ "github.com/ungerik/go3d/quaternion"
 	_ "github.com/ungerik/go3d/quaterniond"
 	_ "github.com/ungerik/go3d/vec2"
 	_ "github.com/ungerik/go3d/vec2d"
+	_ "github.com/ungerik/go3d/vec2i"
 	_ "github.com/ungerik/go3d/vec3"
 	_ "github.com/ungerik/go3d/vec3d"
+	_ "github.com/ungerik/go3d/vec3i"
 	_ "github.com/ungerik/go3d/vec4"
 	_ "github.com/ungerik/go3d/vec4d"
 )
@@ -0,0 +1,158 @@
+// The package frustum contains a float32 view frustum
+// that can be extracted from a view-projection matrix and used for culling.
+package frustum
+
+import (
+	"fmt"
+
+	"github.com/ungerik/go3d/mat4x4"
+	"github.com/ungerik/go3d/vec3"
+)
+
+// Indices of the planes of T.
+// The names refer to normalized device coordinates,
+// so with a flipped Y clip space Bottom and Top are swapped in world space
+// and with a reverse-Z projection Near and Far are swapped.
+const (
+	Left = iota
+	Right
+	Bottom
+	Top
+	Near
+	Far
+)
+
+// Result is the result of testing a point, sphere or box against a frustum.
+type Result int
+
+const (
+	// Outside means the tested volume lies completely outside of the frustum.
+	Outside Result = iota
+	// Intersecting means the tested volume might lie partly inside of the frustum.
+	Intersecting
+	// Inside means the tested volume lies completely inside of the frustum.
+	Inside
+)
+
+// T is a view frustum defined by six normalized planes with normals
+// pointing to the inside of the frustum.
+type T struct {
+	Planes [6]vec3.Plane
+}
+
+// FromMatrix extracts the frustum planes from the view-projection matrix m
+// using the method of Gribb and Hartmann.
+// clip has to be the clip space m was created for.
+// If m is only a projection matrix, the frustum is in view space.
+// For a projection with an infinite far plane, like
+// mat4x4.T.AssignInfinitePerspectiveFov, the plane at infinity has a zero
+// normal and a negative Distance, so every point lies on its inside and it
+// never culls anything. With a reverse-Z projection this is the Near plane.
+func FromMatrix(m *mat4x4.T, clip mat4x4.ClipSpace) T {
+	row := func(i int) [4]float32 {
+		return [4]float32{m[0][i], m[1][i], m[2][i], m[3][i]}
+	}
+	r0, r1, r2, r3 := row(0), row(1), row(2), row(3)
+
+	var self T
+	self.setPlane(Left, r3[0]+r0[0], r3[1]+r0[1], r3[2]+r0[2], r3[3]+r0[3])
+	self.setPlane(Right, r3[0]-r0[0], r3[1]-r0[1], r3[2]-r0[2], r3[3]-r0[3])
+	self.setPlane(Bottom, r3[0]+r1[0], r3[1]+r1[1], r3[2]+r1[2], r3[3]+r1[3])
+	self.setPlane(Top, r3[0]-r1[0], r3[1]-r1[1], r3[2]-r1[2], r3[3]-r1[3])
+	if clip == mat4x4.ClipSpaceOpenGL {
+		self.setPlane(Near, r3[0]+r2[0], r3[1]+r2[1], r3[2]+r2[2], r3[3]+r2[3])
+	} else {
+		self.setPlane(Near, r2[0], r2[1], r2[2], r2[3])
+	}
+	self.setPlane(Far, r3[0]-r2[0], r3[1]-r2[1], r3[2]-r2[2], r3[3]-r2[3])
+	return self
+}
+
+// setPlane sets the plane a*x + b*y + c*z + d = 0.
+func (self *T) setPlane(i int, a, b, c, d float32) {
+	self.Planes[i] = vec3.Plane{Normal: vec3.T{a, b, c}, Distance: -d}
+	self.Planes[i].Normalize()
+}
+
+// String formats T as string.
+func (self *T) String() string {
+	return fmt.Sprintf("%s %s %s %s %s %s",
+		self.Planes[0].String(), self.Planes[1].String(), self.Planes[2].String(),
+		self.Planes[3].String(), self.Planes[4].String(), self.Planes[5].String(),
+	)
+}
+
+// ContainsPoint returns true if p is inside of the frustum or on its border.
+func (self *T) ContainsPoint(p *vec3.T) bool {
+	for i := range self.Planes {
+		if self.Planes[i].SignedDistance(p) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestSphere tests the sphere with center and radius against the frustum.
+// Spheres near the edges of the frustum can be reported as Intersecting
+// although they are Outside.
+func (self *T) TestSphere(center *vec3.T, radius float32) Result {
+	result := Inside
+	for i := range self.Planes {
+		d := self.Planes[i].SignedDistance(center)
+		if d < -radius {
+			return Outside
+		}
+		if d < radius {
+			result = Intersecting
+		}
+	}
+	return result
+}
+
+// TestBox tests box against the frustum.
+// Boxes near the edges of the frustum can be reported as Intersecting
+// although they are Outside.
+func (self *T) TestBox(box *vec3.Box) Result {
+	result := Inside
+	for i := range self.Planes {
+		switch self.Planes[i].ClassifyBox(box) {
+		case vec3.PlaneBack:
+			return Outside
+		case vec3.PlaneStraddling:
+			result = Intersecting
+		}
+	}
+	return result
+}
+
+// Corners returns the eight corner points of the frustum.
+// Bit 0 of the index selects Right over Left, bit 1 Top over Bottom
+// and bit 2 Far over Near, like the corners of vec3.Box.
+// The far corners are not valid for projections with an infinite far plane.
+func (self *T) Corners() [8]vec3.T {
+	var corners [8]vec3.T
+	for i := range corners {
+		x, y, z := Left, Bottom, Near
+		if i&1 != 0 {
+			x = Right
+		}
+		if i&2 != 0 {
+			y = Top
+		}
+		if i&4 != 0 {
+			z = Far
+		}
+		corners[i], _ = vec3.IntersectPlanes(&self.Planes[x], &self.Planes[y], &self.Planes[z])
+	}
+	return corners
+}
+
+// Bounds returns the axis aligned bounding box of the frustum corners.
+func (self *T) Bounds() vec3.Box {
+	corners := self.Corners()
+	box := vec3.EmptyBox
+	for i := range corners {
+		box.ExtendByPoint(&corners[i])
+	}
+	return box
+}
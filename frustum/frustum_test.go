@@ -0,0 +1,175 @@
+package frustum
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/barnex/fmath"
+	"github.com/ungerik/go3d/mat4x4"
+	"github.com/ungerik/go3d/vec3"
+	"github.com/ungerik/go3d/vec4"
+)
+
+// testEpsilon is the relative distance to the frustum borders
+// below which the tests don't compare the classification of points.
+const testEpsilon = 1e-3 //gend:float64 1e-9
+
+var clipSpaces = []struct {
+	name string
+	clip mat4x4.ClipSpace
+}{
+	{"OpenGL", mat4x4.ClipSpaceOpenGL},
+	{"DirectX", mat4x4.ClipSpaceDirectX},
+	{"Vulkan", mat4x4.ClipSpaceVulkan},
+}
+
+var projections = []struct {
+	name     string
+	infinite bool
+	assign   func(m *mat4x4.T, fovY, aspect, znear, zfar float32, clip mat4x4.ClipSpace)
+}{
+	{"PerspectiveFov", false, func(m *mat4x4.T, fovY, aspect, znear, zfar float32, clip mat4x4.ClipSpace) {
+		m.AssignPerspectiveFov(fovY, aspect, znear, zfar, clip)
+	}},
+	{"PerspectiveFovReverseZ", false, func(m *mat4x4.T, fovY, aspect, znear, zfar float32, clip mat4x4.ClipSpace) {
+		m.AssignPerspectiveFovReverseZ(fovY, aspect, znear, zfar, clip)
+	}},
+	{"InfinitePerspectiveFov", true, func(m *mat4x4.T, fovY, aspect, znear, zfar float32, clip mat4x4.ClipSpace) {
+		m.AssignInfinitePerspectiveFov(fovY, aspect, znear, clip)
+	}},
+	{"InfinitePerspectiveFovReverseZ", true, func(m *mat4x4.T, fovY, aspect, znear, zfar float32, clip mat4x4.ClipSpace) {
+		m.AssignInfinitePerspectiveFovReverseZ(fovY, aspect, znear, clip)
+	}},
+}
+
+// clipSlack returns the smallest slack of the clip space inequalities
+// of p relative to w. p is inside of the clip volume if it is not negative.
+func clipSlack(vp *mat4x4.T, p *vec3.T, clip mat4x4.ClipSpace) float32 {
+	c := vp.MulVec4(&vec4.T{p[0], p[1], p[2], 1})
+	w := c[3]
+	slack := []float32{w + c[0], w - c[0], w + c[1], w - c[1], w - c[2]}
+	if clip == mat4x4.ClipSpaceOpenGL {
+		slack = append(slack, w+c[2])
+	} else {
+		slack = append(slack, c[2])
+	}
+	min := slack[0]
+	for _, s := range slack[1:] {
+		if s < min {
+			min = s
+		}
+	}
+	return min / (1 + fmath.Abs(w))
+}
+
+func TestFromMatrix(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	eye := vec3.T{1, 2, 3}
+	center := vec3.T{4, 0, -2}
+	var view mat4x4.T
+	view.AssignLookAt(&eye, &center, &vec3.UnitY)
+
+	for _, cs := range clipSpaces {
+		for _, projection := range projections {
+			var proj, vp mat4x4.T
+			projection.assign(&proj, 1, 1.5, 0.5, 50, cs.clip)
+			vp.AssignMul(&proj, &view)
+			frustum := FromMatrix(&vp, cs.clip)
+
+			inside := 0
+			for n := 0; n < 5000; n++ {
+				p := vec3.T{
+					eye[0] + float32(r.Float64()*120-60),
+					eye[1] + float32(r.Float64()*120-60),
+					eye[2] + float32(r.Float64()*120-60),
+				}
+				slack := clipSlack(&vp, &p, cs.clip)
+				if fmath.Abs(slack) < testEpsilon {
+					continue
+				}
+				if want := slack >= 0; frustum.ContainsPoint(&p) != want {
+					t.Fatalf("%s %s: ContainsPoint(%v) = %v, want %v", cs.name, projection.name, p, !want, want)
+				}
+				if slack >= 0 {
+					inside++
+				}
+			}
+			if inside == 0 {
+				t.Errorf("%s %s: no random point was inside of the frustum", cs.name, projection.name)
+			}
+
+			if projection.infinite {
+				// The plane at infinity has a zero normal and contains every point.
+				infinite := Far
+				if projection.name == "InfinitePerspectiveFovReverseZ" {
+					infinite = Near
+				}
+				plane := frustum.Planes[infinite]
+				if plane.Normal != vec3.Zero || plane.Distance >= 0 {
+					t.Errorf("%s %s: the plane at infinity is %v", cs.name, projection.name, plane)
+				}
+				far := vec3.Sub(&center, &eye)
+				far.Scale(1e6).Add(&eye)
+				if !frustum.ContainsPoint(&far) {
+					t.Errorf("%s %s: the frustum does not contain %v", cs.name, projection.name, far)
+				}
+			}
+		}
+	}
+}
+
+func TestVolumes(t *testing.T) {
+	// A frustum looking down -Z with the field of view 90 degrees,
+	// so its sides are the planes |x| = -z and |y| = -z for z from -1 to -10.
+	spheres := []struct {
+		center vec3.T
+		radius float32
+		want   Result
+	}{
+		{vec3.T{0, 0, -5}, 1, Inside},
+		{vec3.T{3, -3, -5}, 1, Inside},
+		{vec3.T{0, 0, -5}, 10, Intersecting},
+		{vec3.T{0, 0, -10}, 1, Intersecting},
+		{vec3.T{0, 0, -1}, 0.5, Intersecting},
+		{vec3.T{5, 0, -5}, 0.5, Intersecting},
+		{vec3.T{0, 0, 5}, 1, Outside},
+		{vec3.T{0, 0, -20}, 1, Outside},
+		{vec3.T{20, 0, -5}, 1, Outside},
+		{vec3.T{0, -20, -5}, 1, Outside},
+	}
+	boxes := []struct {
+		box  vec3.Box
+		want Result
+	}{
+		{vec3.Box{Min: vec3.T{-1, -1, -6}, Max: vec3.T{1, 1, -4}}, Inside},
+		{vec3.Box{Min: vec3.T{-1, -1, -12}, Max: vec3.T{1, 1, -8}}, Intersecting},
+		{vec3.Box{Min: vec3.T{-1, -1, -2}, Max: vec3.T{1, 1, 0}}, Intersecting},
+		{vec3.Box{Min: vec3.T{-100, -100, -100}, Max: vec3.T{100, 100, 100}}, Intersecting},
+		{vec3.Box{Min: vec3.T{7, -1, -6}, Max: vec3.T{9, 1, -4}}, Outside},
+		{vec3.Box{Min: vec3.T{-1, 7, -6}, Max: vec3.T{1, 9, -4}}, Outside},
+		{vec3.Box{Min: vec3.T{-1, -1, 1}, Max: vec3.T{1, 1, 3}}, Outside},
+		{vec3.Box{Min: vec3.T{-1, -1, -13}, Max: vec3.T{1, 1, -11}}, Outside},
+	}
+
+	for _, cs := range clipSpaces {
+		for _, projection := range projections[:2] {
+			var proj mat4x4.T
+			projection.assign(&proj, math.Pi/2, 1, 1, 10, cs.clip)
+			frustum := FromMatrix(&proj, cs.clip)
+			for _, test := range spheres {
+				if result := frustum.TestSphere(&test.center, test.radius); result != test.want {
+					t.Errorf("%s %s: TestSphere(%v, %f) = %d, want %d", cs.name, projection.name, test.center, test.radius, result, test.want)
+				}
+				if test.want == Inside && !frustum.ContainsPoint(&test.center) {
+					t.Errorf("%s %s: ContainsPoint(%v) = false", cs.name, projection.name, test.center)
+				}
+			}
+			for _, test := range boxes {
+				if result := frustum.TestBox(&test.box); result != test.want {
+					t.Errorf("%s %s: TestBox(%v) = %d, want %d", cs.name, projection.name, test.box, result, test.want)
+				}
+			}
+		}
+	}
+}
@@ -18,8 +18,17 @@ type T interface {
 	Slice() []float32
 
 	// Get returns one element of the vector or matrix.
-	Get(row, col int) float32
+	Get(col, row int) float32
 
 	// IsZero checks if all elements of the vector or matrix are zero.
 	IsZero() bool
 }
+
+// Writable is a T whose elements can be set.
+// All float32 vector and matrix types implement it.
+type Writable interface {
+	T
+
+	// Set sets one element of the vector or matrix.
+	Set(col, row int, value float32)
+}
@@ -0,0 +1,117 @@
+package generic
+
+import (
+	"fmt"
+)
+
+// Add sets dst to the element wise sum of a and b.
+// dst may be a or b. It panics if the shapes differ.
+func Add(dst Writable, a, b T) {
+	mustHaveShape(a, dst.Cols(), dst.Rows())
+	mustHaveShape(b, dst.Cols(), dst.Rows())
+	for col := 0; col < dst.Cols(); col++ {
+		for row := 0; row < dst.Rows(); row++ {
+			dst.Set(col, row, a.Get(col, row)+b.Get(col, row))
+		}
+	}
+}
+
+// Scale sets dst to a with all elements multiplied by f.
+// dst may be a. It panics if the shapes differ.
+func Scale(dst Writable, a T, f float32) {
+	mustHaveShape(a, dst.Cols(), dst.Rows())
+	for col := 0; col < dst.Cols(); col++ {
+		for row := 0; row < dst.Rows(); row++ {
+			dst.Set(col, row, a.Get(col, row)*f)
+		}
+	}
+}
+
+// Equal returns if a and b have the same shape and no element
+// differs by more than tolerance.
+func Equal(a, b T, tolerance float32) bool {
+	if a.Cols() != b.Cols() || a.Rows() != b.Rows() {
+		return false
+	}
+	return MaxAbsDiff(a, b) <= tolerance
+}
+
+// MaxAbsDiff returns the largest absolute difference between
+// the elements of a and b. It panics if the shapes differ.
+func MaxAbsDiff(a, b T) float32 {
+	mustHaveShape(b, a.Cols(), a.Rows())
+	var max float32
+	for col := 0; col < a.Cols(); col++ {
+		for row := 0; row < a.Rows(); row++ {
+			d := a.Get(col, row) - b.Get(col, row)
+			if d < 0 {
+				d = -d
+			}
+			if d > max {
+				max = d
+			}
+		}
+	}
+	return max
+}
+
+// Transpose sets dst to the transpose of a.
+// dst may be a. It panics if the shape of dst is not the transposed shape of a.
+func Transpose(dst Writable, a T) {
+	mustHaveShape(dst, a.Rows(), a.Cols())
+	ae := elements(a)
+	rows := a.Rows()
+	for col := 0; col < a.Cols(); col++ {
+		for row := 0; row < rows; row++ {
+			dst.Set(row, col, ae[col*rows+row])
+		}
+	}
+}
+
+// Multiply sets dst to the matrix product of a and b.
+// dst may be a or b. It panics if the number of columns of a
+// differs from the number of rows of b or if dst has not
+// the rows of a and the columns of b.
+func Multiply(dst Writable, a, b T) {
+	if a.Cols() != b.Rows() {
+		panic(fmt.Sprintf("generic: can't multiply %dx%d by %dx%d", a.Rows(), a.Cols(), b.Rows(), b.Cols()))
+	}
+	mustHaveShape(dst, b.Cols(), a.Rows())
+	ae := elements(a)
+	be := elements(b)
+	aRows, n := a.Rows(), a.Cols()
+	for col := 0; col < b.Cols(); col++ {
+		for row := 0; row < aRows; row++ {
+			var sum float32
+			for i := 0; i < n; i++ {
+				sum += ae[i*aRows+row] * be[col*n+i]
+			}
+			dst.Set(col, row, sum)
+		}
+	}
+}
+
+// Copy sets dst to the elements of a. It panics if the shapes differ.
+func Copy(dst Writable, a T) {
+	Scale(dst, a, 1)
+}
+
+// elements returns a copy of the elements of t column by column.
+// It uses Get instead of Slice, because the order of Slice is not
+// specified, and the copy allows dst to alias the arguments.
+func elements(t T) []float32 {
+	cols, rows := t.Cols(), t.Rows()
+	e := make([]float32, cols*rows)
+	for col := 0; col < cols; col++ {
+		for row := 0; row < rows; row++ {
+			e[col*rows+row] = t.Get(col, row)
+		}
+	}
+	return e
+}
+
+func mustHaveShape(t T, cols, rows int) {
+	if t.Cols() != cols || t.Rows() != rows {
+		panic(fmt.Sprintf("generic: expected %dx%d shape but got %dx%d", rows, cols, t.Rows(), t.Cols()))
+	}
+}
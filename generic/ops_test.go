@@ -0,0 +1,88 @@
+package generic
+
+import (
+	"testing"
+)
+
+// rowMajor is a Writable that stores its elements row by row,
+// so its Slice has a different order than the go3d types.
+type rowMajor struct {
+	rows, cols int
+	data       []float32
+}
+
+func newRowMajor(rows, cols int, data ...float32) *rowMajor {
+	return &rowMajor{rows, cols, data}
+}
+
+func (self *rowMajor) Cols() int                       { return self.cols }
+func (self *rowMajor) Rows() int                       { return self.rows }
+func (self *rowMajor) Size() int                       { return self.rows * self.cols }
+func (self *rowMajor) Slice() []float32                { return self.data }
+func (self *rowMajor) Get(col, row int) float32        { return self.data[row*self.cols+col] }
+func (self *rowMajor) Set(col, row int, value float32) { self.data[row*self.cols+col] = value }
+func (self *rowMajor) IsZero() bool {
+	for _, v := range self.data {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTranspose(t *testing.T) {
+	a := newRowMajor(2, 3,
+		1, 2, 3,
+		4, 5, 6,
+	)
+	dst := newRowMajor(3, 2, make([]float32, 6)...)
+	Transpose(dst, a)
+	want := newRowMajor(3, 2,
+		1, 4,
+		2, 5,
+		3, 6,
+	)
+	if !Equal(dst, want, 0) {
+		t.Errorf("Transpose = %v, want %v", dst.data, want.data)
+	}
+
+	// The square matrix is transposed in place and its Slice aliases its memory.
+	m := newRowMajor(2, 2,
+		1, 2,
+		3, 4,
+	)
+	Transpose(m, m)
+	if want := newRowMajor(2, 2, 1, 3, 2, 4); !Equal(m, want, 0) {
+		t.Errorf("Transpose in place = %v, want %v", m.data, want.data)
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	a := newRowMajor(2, 3,
+		1, 2, 3,
+		4, 5, 6,
+	)
+	b := newRowMajor(3, 2,
+		7, 8,
+		9, 10,
+		11, 12,
+	)
+	dst := newRowMajor(2, 2, make([]float32, 4)...)
+	Multiply(dst, a, b)
+	want := newRowMajor(2, 2,
+		58, 64,
+		139, 154,
+	)
+	if !Equal(dst, want, 0) {
+		t.Errorf("Multiply = %v, want %v", dst.data, want.data)
+	}
+
+	m := newRowMajor(2, 2,
+		1, 2,
+		3, 4,
+	)
+	Multiply(m, m, m)
+	if want := newRowMajor(2, 2, 7, 10, 15, 22); !Equal(m, want, 0) {
+		t.Errorf("Multiply in place = %v, want %v", m.data, want.data)
+	}
+}
@@ -0,0 +1,33 @@
+package genericd
+
+import (
+	"fmt"
+
+	"github.com/ungerik/go3d/generic"
+)
+
+// FromGeneric sets dst to the elements of the float32 src.
+// It panics if the shapes differ.
+func FromGeneric(dst Writable, src generic.T) {
+	if src.Cols() != dst.Cols() || src.Rows() != dst.Rows() {
+		panic(fmt.Sprintf("genericd: can't convert %dx%d to %dx%d", src.Rows(), src.Cols(), dst.Rows(), dst.Cols()))
+	}
+	for col := 0; col < dst.Cols(); col++ {
+		for row := 0; row < dst.Rows(); row++ {
+			dst.Set(col, row, float64(src.Get(col, row)))
+		}
+	}
+}
+
+// ToGeneric sets the float32 dst to the elements of src.
+// It panics if the shapes differ.
+func ToGeneric(dst generic.Writable, src T) {
+	if src.Cols() != dst.Cols() || src.Rows() != dst.Rows() {
+		panic(fmt.Sprintf("genericd: can't convert %dx%d to %dx%d", src.Rows(), src.Cols(), dst.Rows(), dst.Cols()))
+	}
+	for col := 0; col < dst.Cols(); col++ {
+		for row := 0; row < dst.Rows(); row++ {
+			dst.Set(col, row, float32(src.Get(col, row)))
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build go1.18
+// +build go1.18
+
+package go3d
+
+// Import the sub-packages that need generics only for Go 1.18 or later,
+// so the other packages still build with older versions.
+import (
+	_ "github.com/ungerik/go3d/vecn"
+)
@@ -0,0 +1,471 @@
+// The package gjk contains float32 collision detection between convex shapes
+// using the Gilbert-Johnson-Keerthi (GJK) algorithm for overlap and distance
+// queries and the expanding polytope algorithm (EPA) for penetration depth.
+// The shapes only have to implement the Convex interface.
+package gjk
+
+import (
+	"github.com/ungerik/go3d/vec3"
+)
+
+const (
+	// epsilon is the relative tolerance for the termination of GJK and EPA.
+	epsilon = 1e-5 //gend:float64 1e-10
+
+	// maxIterations limits GJK and EPA for degenerate input.
+	maxIterations = 64
+)
+
+// Convex is implemented by convex shapes like vec3.Box, vec3.Sphere,
+// vec3.Capsule, obb.T or Points.
+type Convex interface {
+
+	// Support returns the point of the shape that is farthest in direction dir.
+	// dir does not need to be normalized and can be zero.
+	Support(dir *vec3.T) vec3.T
+}
+
+// Points is the convex hull of a set of points.
+// It must contain at least one point.
+type Points []vec3.T
+
+// Support implements the Convex interface.
+// It panics if self is empty.
+func (self Points) Support(dir *vec3.T) vec3.T {
+	if len(self) == 0 {
+		panic("gjk: Support of empty Points")
+	}
+	best := 0
+	bestDot := vec3.Dot(&self[0], dir)
+	for i := 1; i < len(self); i++ {
+		if d := vec3.Dot(&self[i], dir); d > bestDot {
+			best = i
+			bestDot = d
+		}
+	}
+	return self[best]
+}
+
+// Intersects returns true if a and b overlap or touch.
+func Intersects(a, b Convex) bool {
+	var s simplex
+	_, intersecting := s.run(a, b, true)
+	return intersecting
+}
+
+// Distance returns the distance between a and b and the closest points
+// pa on a and pb on b. If a and b intersect, the distance is zero.
+func Distance(a, b Convex) (distance float32, pa, pb vec3.T) {
+	var s simplex
+	v, intersecting := s.run(a, b, false)
+	pa, pb = s.witnessPoints()
+	if intersecting {
+		return 0, pa, pb
+	}
+	return v.Length(), pa, pb
+}
+
+// Penetration returns the penetration depth of intersecting shapes a and b
+// and the contact normal, which is the direction in which b has to be moved
+// by depth to separate the shapes. ok is false if a and b don't intersect.
+func Penetration(a, b Convex) (normal vec3.T, depth float32, ok bool) {
+	var s simplex
+	if _, intersecting := s.run(a, b, false); !intersecting {
+		return vec3.Zero, 0, false
+	}
+	if !s.blowUp(a, b) {
+		// The Minkowski difference is flat, so the shapes only touch.
+		return s.touchingNormal(), 0, true
+	}
+	normal, depth = expandPolytope(a, b, &s)
+	return normal, depth, true
+}
+
+// vertex is a point of the Minkowski difference a - b
+// together with the support points of a and b it was created from.
+type vertex struct {
+	w vec3.T
+	a vec3.T
+	b vec3.T
+}
+
+func support(a, b Convex, dir *vec3.T) vertex {
+	pa := a.Support(dir)
+	negDir := dir.Inverted()
+	pb := b.Support(&negDir)
+	return vertex{vec3.Sub(&pa, &pb), pa, pb}
+}
+
+// simplex holds up to four vertices and the barycentric weights
+// of the point of the simplex that is closest to the origin.
+type simplex struct {
+	v      [4]vertex
+	lambda [4]float32
+	n      int
+}
+
+// run executes GJK and leaves the simplex closest to the origin in self.
+// It returns the point of the Minkowski difference that is closest
+// to the origin. If earlyOut is true, it stops as soon as a separating
+// axis is found, so the returned point is only an approximation then.
+func (self *simplex) run(a, b Convex, earlyOut bool) (v vec3.T, intersecting bool) {
+	self.v[0] = support(a, b, &vec3.UnitX)
+	self.lambda[0] = 1
+	self.n = 1
+	v = self.v[0].w
+
+	for i := 0; i < maxIterations; i++ {
+		vv := v.LengthSqr()
+		if vv <= epsilon*epsilon*self.maxLengthSqr() {
+			return v, true
+		}
+		dir := v.Inverted()
+		w := support(a, b, &dir)
+		vw := vec3.Dot(&v, &w.w)
+		if earlyOut && vw > 0 {
+			return v, false
+		}
+		if vv-vw <= epsilon*vv || self.contains(&w.w) {
+			return v, false
+		}
+		self.v[self.n] = w
+		self.n++
+		v = self.reduce()
+		if self.n == 4 {
+			return v, true
+		}
+	}
+	return v, false
+}
+
+func (self *simplex) maxLengthSqr() float32 {
+	var max float32
+	for i := 0; i < self.n; i++ {
+		if l := self.v[i].w.LengthSqr(); l > max {
+			max = l
+		}
+	}
+	return max
+}
+
+func (self *simplex) contains(w *vec3.T) bool {
+	for i := 0; i < self.n; i++ {
+		if self.v[i].w == *w {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *simplex) witnessPoints() (pa, pb vec3.T) {
+	for i := 0; i < self.n; i++ {
+		a := self.v[i].a.Scaled(self.lambda[i])
+		b := self.v[i].b.Scaled(self.lambda[i])
+		pa.Add(&a)
+		pb.Add(&b)
+	}
+	return pa, pb
+}
+
+// set reduces the simplex to the vertices with the given indices and weights.
+func (self *simplex) set(indices []int, lambda []float32) vec3.T {
+	var v [4]vertex
+	for i, index := range indices {
+		v[i] = self.v[index]
+	}
+	self.v = v
+	self.n = len(indices)
+	var p vec3.T
+	for i := range lambda {
+		self.lambda[i] = lambda[i]
+		w := self.v[i].w.Scaled(lambda[i])
+		p.Add(&w)
+	}
+	return p
+}
+
+// reduce finds the point of the simplex that is closest to the origin,
+// reduces the simplex to the smallest sub-simplex containing that point
+// and returns it. If the simplex is a tetrahedron that contains the origin,
+// it is kept unchanged.
+func (self *simplex) reduce() vec3.T {
+	switch self.n {
+	case 2:
+		return self.reduceSegment(0, 1)
+	case 3:
+		return self.reduceTriangle(0, 1, 2)
+	default:
+		return self.reduceTetrahedron()
+	}
+}
+
+func (self *simplex) reduceSegment(i, j int) vec3.T {
+	a, b := &self.v[i].w, &self.v[j].w
+	ab := vec3.Sub(b, a)
+	t := -vec3.Dot(a, &ab)
+	if t <= 0 {
+		return self.set([]int{i}, []float32{1})
+	}
+	denom := ab.LengthSqr()
+	if t >= denom {
+		return self.set([]int{j}, []float32{1})
+	}
+	t /= denom
+	return self.set([]int{i, j}, []float32{1 - t, t})
+}
+
+// reduceTriangle works like Triangle.ClosestPoint for the origin.
+func (self *simplex) reduceTriangle(i, j, k int) vec3.T {
+	a, b, c := &self.v[i].w, &self.v[j].w, &self.v[k].w
+	ab := vec3.Sub(b, a)
+	ac := vec3.Sub(c, a)
+	d1 := -vec3.Dot(&ab, a)
+	d2 := -vec3.Dot(&ac, a)
+	if d1 <= 0 && d2 <= 0 {
+		return self.set([]int{i}, []float32{1})
+	}
+
+	d3 := -vec3.Dot(&ab, b)
+	d4 := -vec3.Dot(&ac, b)
+	if d3 >= 0 && d4 <= d3 {
+		return self.set([]int{j}, []float32{1})
+	}
+
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		t := d1 / (d1 - d3)
+		return self.set([]int{i, j}, []float32{1 - t, t})
+	}
+
+	d5 := -vec3.Dot(&ab, c)
+	d6 := -vec3.Dot(&ac, c)
+	if d6 >= 0 && d5 <= d6 {
+		return self.set([]int{k}, []float32{1})
+	}
+
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		t := d2 / (d2 - d6)
+		return self.set([]int{i, k}, []float32{1 - t, t})
+	}
+
+	va := d3*d6 - d5*d4
+	if va <= 0 && (d4-d3) >= 0 && (d5-d6) >= 0 {
+		t := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return self.set([]int{j, k}, []float32{1 - t, t})
+	}
+
+	ooDenom := 1 / (va + vb + vc)
+	v := vb * ooDenom
+	w := vc * ooDenom
+	return self.set([]int{i, j, k}, []float32{1 - v - w, v, w})
+}
+
+func (self *simplex) reduceTetrahedron() vec3.T {
+	faces := [4][4]int{{0, 1, 2, 3}, {0, 2, 3, 1}, {0, 3, 1, 2}, {1, 3, 2, 0}}
+	bestDist := float32(-1)
+	var best simplex
+	var bestPoint vec3.T
+	for _, f := range faces {
+		if !originOutsideOfPlane(&self.v[f[0]].w, &self.v[f[1]].w, &self.v[f[2]].w, &self.v[f[3]].w) {
+			continue
+		}
+		s := *self
+		p := s.reduceTriangle(f[0], f[1], f[2])
+		if d := p.LengthSqr(); bestDist < 0 || d < bestDist {
+			bestDist = d
+			best = s
+			bestPoint = p
+		}
+	}
+	if bestDist < 0 {
+		// The origin is inside of the tetrahedron.
+		return vec3.Zero
+	}
+	*self = best
+	return bestPoint
+}
+
+// originOutsideOfPlane returns true if the origin is on the other side
+// of the plane through a, b, c than d or on the plane.
+func originOutsideOfPlane(a, b, c, d *vec3.T) bool {
+	ab := vec3.Sub(b, a)
+	ac := vec3.Sub(c, a)
+	n := vec3.Cross(&ab, &ac)
+	ad := vec3.Sub(d, a)
+	signOrigin := -vec3.Dot(a, &n)
+	signD := vec3.Dot(&ad, &n)
+	return signOrigin*signD <= 0
+}
+
+// blowUp extends a simplex that contains the origin to a tetrahedron.
+// It returns false if that is not possible because the
+// Minkowski difference of a and b is flat.
+func (self *simplex) blowUp(a, b Convex) bool {
+	if self.n == 1 {
+		for i := 0; i < 6 && self.n < 2; i++ {
+			var dir vec3.T
+			dir[i%3] = float32(1 - 2*(i/3))
+			self.tryAdd(support(a, b, &dir), 1)
+		}
+	}
+	if self.n == 2 {
+		d := vec3.Sub(&self.v[1].w, &self.v[0].w)
+		for i := 0; i < 3 && self.n < 3; i++ {
+			var axis vec3.T
+			axis[i] = 1
+			dir := vec3.Cross(&d, &axis)
+			self.tryAdd(support(a, b, &dir), 2)
+			dir.Invert()
+			self.tryAdd(support(a, b, &dir), 2)
+		}
+	}
+	if self.n == 3 {
+		ab := vec3.Sub(&self.v[1].w, &self.v[0].w)
+		ac := vec3.Sub(&self.v[2].w, &self.v[0].w)
+		dir := vec3.Cross(&ab, &ac)
+		self.tryAdd(support(a, b, &dir), 3)
+		dir.Invert()
+		self.tryAdd(support(a, b, &dir), 3)
+	}
+	return self.n == 4
+}
+
+// tryAdd adds w as vertex number n if the simplex has n vertices
+// and w is not part of the affine hull of the simplex.
+func (self *simplex) tryAdd(w vertex, n int) {
+	if self.n != n {
+		return
+	}
+	scale := self.maxLengthSqr()
+	if l := w.w.LengthSqr(); l > scale {
+		scale = l
+	}
+	p := vec3.Sub(&w.w, &self.v[0].w)
+	var degenerate bool
+	switch n {
+	case 1:
+		degenerate = p.LengthSqr() <= epsilon*epsilon*scale
+	case 2:
+		d := vec3.Sub(&self.v[1].w, &self.v[0].w)
+		c := vec3.Cross(&d, &p)
+		degenerate = c.LengthSqr() <= epsilon*epsilon*scale*scale
+	case 3:
+		ab := vec3.Sub(&self.v[1].w, &self.v[0].w)
+		ac := vec3.Sub(&self.v[2].w, &self.v[0].w)
+		c := vec3.Cross(&ab, &ac)
+		d := vec3.Dot(&c, &p)
+		degenerate = d*d <= epsilon*epsilon*scale*scale*scale
+	}
+	if !degenerate {
+		self.v[n] = w
+		self.n++
+	}
+}
+
+// touchingNormal returns a normal for shapes with a flat Minkowski difference.
+func (self *simplex) touchingNormal() vec3.T {
+	if self.n >= 3 {
+		ab := vec3.Sub(&self.v[1].w, &self.v[0].w)
+		ac := vec3.Sub(&self.v[2].w, &self.v[0].w)
+		n := vec3.Cross(&ab, &ac)
+		return n.Normalized()
+	}
+	if self.n == 2 {
+		d := vec3.Sub(&self.v[1].w, &self.v[0].w)
+		return d.Normal()
+	}
+	return vec3.UnitX
+}
+
+type face struct {
+	i, j, k  int
+	normal   vec3.T
+	distance float32
+}
+
+func newFace(vertices []vertex, i, j, k int) face {
+	ab := vec3.Sub(&vertices[j].w, &vertices[i].w)
+	ac := vec3.Sub(&vertices[k].w, &vertices[i].w)
+	n := vec3.Cross(&ab, &ac)
+	if n.IsZero() {
+		// Degenerate faces must never be selected as closest face.
+		return face{i, j, k, n, vec3.MaxVal[0]}
+	}
+	n.Normalize()
+	return face{i, j, k, n, vec3.Dot(&n, &vertices[i].w)}
+}
+
+type edge struct {
+	i, j int
+}
+
+// expandPolytope runs EPA starting with the tetrahedron in s
+// that contains the origin.
+func expandPolytope(a, b Convex, s *simplex) (normal vec3.T, depth float32) {
+	vertices := make([]vertex, 4, 4+maxIterations)
+	copy(vertices, s.v[:])
+	faces := make([]face, 0, 4+2*maxIterations)
+	for _, f := range [4][4]int{{0, 1, 2, 3}, {0, 2, 3, 1}, {0, 3, 1, 2}, {1, 3, 2, 0}} {
+		ff := newFace(vertices, f[0], f[1], f[2])
+		opposite := vec3.Sub(&vertices[f[3]].w, &vertices[f[0]].w)
+		if vec3.Dot(&ff.normal, &opposite) > 0 {
+			ff = newFace(vertices, f[0], f[2], f[1])
+		}
+		faces = append(faces, ff)
+	}
+
+	var closest face
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		closest = faces[0]
+		for _, f := range faces[1:] {
+			if f.distance < closest.distance {
+				closest = f
+			}
+		}
+
+		w := support(a, b, &closest.normal)
+		d := vec3.Dot(&w.w, &closest.normal)
+		if d-closest.distance <= epsilon*(1+abs(d)) {
+			break
+		}
+
+		var horizon []edge
+		kept := faces[:0]
+		for _, f := range faces {
+			offset := vec3.Sub(&w.w, &vertices[f.i].w)
+			if vec3.Dot(&f.normal, &offset) <= 0 {
+				kept = append(kept, f)
+				continue
+			}
+			for _, e := range [3]edge{{f.i, f.j}, {f.j, f.k}, {f.k, f.i}} {
+				horizon = toggleEdge(horizon, e)
+			}
+		}
+		faces = kept
+		vertices = append(vertices, w)
+		n := len(vertices) - 1
+		for _, e := range horizon {
+			faces = append(faces, newFace(vertices, e.i, e.j, n))
+		}
+	}
+	return closest.normal, closest.distance
+}
+
+// toggleEdge adds e to edges or removes its reversed twin
+// which is shared with another visible face.
+func toggleEdge(edges []edge, e edge) []edge {
+	for i, other := range edges {
+		if other.i == e.j && other.j == e.i {
+			return append(edges[:i], edges[i+1:]...)
+		}
+	}
+	return append(edges, e)
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
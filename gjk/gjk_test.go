@@ -0,0 +1,199 @@
+package gjk
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/barnex/fmath"
+	"github.com/ungerik/go3d/mat3x3"
+	"github.com/ungerik/go3d/obb"
+	"github.com/ungerik/go3d/vec3"
+)
+
+const (
+	// distanceEpsilon is the tolerance of distances of shapes with flat sides.
+	distanceEpsilon = 1e-3 //gend:float64 1e-6
+
+	// curvedEpsilon is the tolerance of distances and penetration depths
+	// of curved shapes, which GJK and EPA only approximate by polytopes.
+	curvedEpsilon = 2e-2
+)
+
+var (
+	_ Convex = &vec3.Box{}
+	_ Convex = &vec3.Sphere{}
+	_ Convex = &vec3.Capsule{}
+	_ Convex = &vec3.Segment{}
+	_ Convex = &vec3.Triangle{}
+	_ Convex = &obb.T{}
+	_ Convex = Points{}
+)
+
+func random(r *rand.Rand, min, max float32) float32 {
+	return min + float32(r.Float64())*(max-min)
+}
+
+func randomPoint(r *rand.Rand, min, max float32) vec3.T {
+	return vec3.T{random(r, min, max), random(r, min, max), random(r, min, max)}
+}
+
+// shapeAt returns shapes whose nearest point to the box -1..1
+// has the X coordinate 1 + gap. Their penetration depth is -gap
+// in the direction of the X axis for small negative gaps.
+var shapeAt = []struct {
+	name   string
+	curved bool
+	shape  func(gap float32) Convex
+}{
+	{"Box", false, func(gap float32) Convex {
+		return &vec3.Box{Min: vec3.T{1 + gap, -0.5, -0.5}, Max: vec3.T{3 + gap, 0.5, 0.5}}
+	}},
+	{"Sphere", true, func(gap float32) Convex {
+		return &vec3.Sphere{Center: vec3.T{2 + gap, 0, 0}, Radius: 1}
+	}},
+	{"Capsule", true, func(gap float32) Convex {
+		return &vec3.Capsule{Segment: vec3.Segment{A: vec3.T{2 + gap, -0.5, 0}, B: vec3.T{2 + gap, 0.5, 0}}, Radius: 1}
+	}},
+	{"Segment", false, func(gap float32) Convex {
+		return &vec3.Segment{A: vec3.T{1 + gap, 0, 0}, B: vec3.T{3 + gap, 0.5, 0.5}}
+	}},
+	{"Triangle", false, func(gap float32) Convex {
+		return &vec3.Triangle{A: vec3.T{1 + gap, 0, 0}, B: vec3.T{3 + gap, 1, 0}, C: vec3.T{3 + gap, -1, 0.5}}
+	}},
+	{"OBB", false, func(gap float32) Convex {
+		var axes mat3x3.T
+		axes.AssignZRotation(0.25)
+		// The corner of the box in the direction of -X lies at gap + 1.
+		box := obb.T{Axes: axes, HalfExtents: vec3.T{0.5, 0.5, 0.5}}
+		corner := box.Support(&vec3.T{-1, 0, 0})
+		box.Center = vec3.T{1 + gap - corner[0], 0, 0}
+		return &box
+	}},
+	{"Points", false, func(gap float32) Convex {
+		return Points{{1 + gap, 0, 0}, {2 + gap, 1, 0}, {2 + gap, -1, 1}, {2 + gap, 0, -1}}
+	}},
+}
+
+func TestShapes(t *testing.T) {
+	box := &vec3.Box{Min: vec3.T{-1, -1, -1}, Max: vec3.T{1, 1, 1}}
+	for _, test := range shapeAt {
+		tolerance := float32(distanceEpsilon)
+		if test.curved {
+			tolerance = curvedEpsilon
+		}
+
+		separated := test.shape(0.5)
+		if Intersects(box, separated) {
+			t.Errorf("%s: separated shapes intersect", test.name)
+		}
+		d, pa, pb := Distance(box, separated)
+		if fmath.Abs(d-0.5) > tolerance {
+			t.Errorf("%s: distance of separated shapes is %f, want 0.5", test.name, d)
+		}
+		if w := vec3.Sub(&pb, &pa); fmath.Abs(w.Length()-d) > tolerance {
+			t.Errorf("%s: closest points %v and %v are not %f apart", test.name, pa, pb, d)
+		}
+		if _, _, ok := Penetration(box, separated); ok {
+			t.Errorf("%s: separated shapes penetrate", test.name)
+		}
+
+		touching := test.shape(0)
+		if d, _, _ := Distance(box, touching); d > tolerance {
+			t.Errorf("%s: distance of touching shapes is %f", test.name, d)
+		}
+		// Curved shapes touch in a single point that GJK only approaches.
+		if !test.curved && !Intersects(box, touching) {
+			t.Errorf("%s: touching shapes don't intersect", test.name)
+		}
+
+		overlapping := test.shape(-0.25)
+		if !Intersects(box, overlapping) {
+			t.Errorf("%s: overlapping shapes don't intersect", test.name)
+		}
+		if d, _, _ := Distance(box, overlapping); d != 0 {
+			t.Errorf("%s: distance of overlapping shapes is %f", test.name, d)
+		}
+		normal, depth, ok := Penetration(box, overlapping)
+		if !ok || fmath.Abs(depth-0.25) > tolerance || fmath.Abs(normal[0]-1) > tolerance {
+			t.Errorf("%s: Penetration = %v, %f, %v, want %v, 0.25, true", test.name, normal, depth, ok, vec3.UnitX)
+		}
+	}
+}
+
+func TestSpheres(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 1000; n++ {
+		a := &vec3.Sphere{Center: randomPoint(r, 0, 4), Radius: random(r, 0.1, 1.1)}
+		b := &vec3.Sphere{Center: randomPoint(r, 0, 4), Radius: random(r, 0.1, 1.1)}
+		ab := vec3.Sub(&b.Center, &a.Center)
+		want := ab.Length() - a.Radius - b.Radius
+		if fmath.Abs(want) < curvedEpsilon {
+			continue
+		}
+		if Intersects(a, b) != (want < 0) {
+			t.Fatalf("Intersects(%v, %v) = %v, want %v", a, b, !(want < 0), want < 0)
+		}
+		if want > 0 {
+			if d, _, _ := Distance(a, b); fmath.Abs(d-want) > curvedEpsilon {
+				t.Fatalf("Distance(%v, %v) = %f, want %f", a, b, d, want)
+			}
+		} else {
+			_, depth, ok := Penetration(a, b)
+			if !ok || fmath.Abs(depth+want) > curvedEpsilon {
+				t.Fatalf("Penetration(%v, %v) = %f, %v, want %f", a, b, depth, ok, -want)
+			}
+		}
+	}
+}
+
+func TestBoxes(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for n := 0; n < 1000; n++ {
+		var boxes [2]vec3.Box
+		for i := range boxes {
+			size := randomPoint(r, 0.5, 1.5)
+			boxes[i].Min = randomPoint(r, 0, 2)
+			boxes[i].Max = vec3.Add(&boxes[i].Min, &size)
+		}
+		a, b := &boxes[0], &boxes[1]
+
+		// The distance is the length of the gaps along the axes and the
+		// penetration depth is the smallest overlap along the axes.
+		var gap vec3.T
+		overlap := vec3.MaxVal[0]
+		for i := 0; i < 3; i++ {
+			if g := b.Min[i] - a.Max[i]; g > 0 {
+				gap[i] = g
+			} else if g := a.Min[i] - b.Max[i]; g > 0 {
+				gap[i] = g
+			}
+			if o := a.Max[i] - b.Min[i]; o < overlap {
+				overlap = o
+			}
+			if o := b.Max[i] - a.Min[i]; o < overlap {
+				overlap = o
+			}
+		}
+
+		if Intersects(a, b) != a.Intersects(b) {
+			t.Fatalf("Intersects(%v, %v) = %v, want %v", a, b, !a.Intersects(b), a.Intersects(b))
+		}
+		if d, _, _ := Distance(a, b); fmath.Abs(d-gap.Length()) > distanceEpsilon {
+			t.Fatalf("Distance(%v, %v) = %f, want %f", a, b, d, gap.Length())
+		}
+		if a.Intersects(b) {
+			if _, depth, ok := Penetration(a, b); !ok || fmath.Abs(depth-overlap) > distanceEpsilon {
+				t.Fatalf("Penetration(%v, %v) = %f, %v, want %f", a, b, depth, ok, overlap)
+			}
+		}
+	}
+}
+
+func TestEmptyPoints(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Support of empty Points did not panic")
+		}
+	}()
+	Points{}.Support(&vec3.UnitX)
+}
@@ -0,0 +1,260 @@
+// The package hashgrid2 contains a uniform grid spatial hash
+// for neighbour queries between vec2.T points, like particles.
+//
+// The points are hashed by their integer cell coordinates and
+// sorted into a flat table with a counting sort, so rebuilding
+// the grid every frame doesn't allocate once the buffers have grown.
+// Queries don't modify the grid and are safe for concurrent use.
+package hashgrid2
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/barnex/fmath"
+	"github.com/ungerik/go3d/vec2"
+)
+
+// Cell holds the integer coordinates of a grid cell.
+type Cell [2]int32
+
+// T is a spatial hash grid.
+type T struct {
+	cellSize    float32
+	invCellSize float32
+	points      []vec2.T
+	// hashes holds the bucket of every point.
+	hashes []uint32
+	// start holds the offset of every bucket into indices
+	// and the total number of points as last element.
+	start   []int32
+	indices []int32
+}
+
+// New returns an empty grid with cells of cellSize.
+// For radius queries cellSize is best chosen close to the radius.
+func New(cellSize float32) *T {
+	return &T{cellSize: cellSize, invCellSize: 1 / cellSize}
+}
+
+// String returns a description of the grid.
+func (self *T) String() string {
+	return fmt.Sprintf("hashgrid2 with %d points and cell size %f", len(self.points), self.cellSize)
+}
+
+// CellSize returns the size of the cells.
+func (self *T) CellSize() float32 {
+	return self.cellSize
+}
+
+// Points returns the points the grid was built from.
+func (self *T) Points() []vec2.T {
+	return self.points
+}
+
+// Cell returns the cell containing p.
+// Coordinates beyond the int32 range are clamped to it.
+func (self *T) Cell(p *vec2.T) Cell {
+	return Cell{
+		self.cellCoord(p[0]),
+		self.cellCoord(p[1]),
+	}
+}
+
+// cellCoord returns the cell coordinate of x clamped to the int32 range.
+func (self *T) cellCoord(x float32) int32 {
+	c := fmath.Floor(x * self.invCellSize)
+	switch {
+	case c < math.MinInt32:
+		return math.MinInt32
+	case c >= math.MaxInt32:
+		return math.MaxInt32
+	}
+	return int32(c)
+}
+
+func (self *T) bucket(c *Cell) uint32 {
+	h := uint32(c[0])*73856093 ^ uint32(c[1])*19349663
+	return h & uint32(len(self.start)-2)
+}
+
+// reset sizes the buffers for points reusing their memory.
+func (self *T) reset(points []vec2.T) {
+	self.points = points
+	numBuckets := 1
+	for numBuckets < len(points) {
+		numBuckets <<= 1
+	}
+	self.start = resizeInt32(self.start, numBuckets+1)
+	self.indices = resizeInt32(self.indices, len(points))
+	if cap(self.hashes) < len(points) {
+		self.hashes = make([]uint32, len(points))
+	}
+	self.hashes = self.hashes[:len(points)]
+}
+
+// Rebuild sorts points into the grid.
+// The grid references points, so they must not be modified while it is used.
+func (self *T) Rebuild(points []vec2.T) {
+	self.reset(points)
+	start := self.start
+	for i := range points {
+		c := self.Cell(&points[i])
+		h := self.bucket(&c)
+		self.hashes[i] = h
+		start[h]++
+	}
+	prefixSum(start)
+	// Fill the buckets from the back, which leaves start
+	// pointing at the first index of every bucket.
+	for i := len(points) - 1; i >= 0; i-- {
+		h := self.hashes[i]
+		start[h]--
+		self.indices[start[h]] = int32(i)
+	}
+}
+
+// RebuildParallel is like Rebuild but distributes the work across
+// workers goroutines. If workers is zero or less, runtime.GOMAXPROCS(0) is used.
+// The order of the points within a cell is unspecified.
+func (self *T) RebuildParallel(points []vec2.T, workers int) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	self.reset(points)
+	start := self.start
+
+	parallel(len(points), workers, func(begin, end int) {
+		for i := begin; i < end; i++ {
+			c := self.Cell(&points[i])
+			h := self.bucket(&c)
+			self.hashes[i] = h
+			atomic.AddInt32(&start[h], 1)
+		}
+	})
+	prefixSum(start)
+	parallel(len(points), workers, func(begin, end int) {
+		for i := begin; i < end; i++ {
+			h := self.hashes[i]
+			self.indices[atomic.AddInt32(&start[h], -1)] = int32(i)
+		}
+	})
+}
+
+// ForEachNeighbour calls f with the index and squared distance
+// of every point within radius of p. A negative radius matches no points.
+// If the radius covers more cells than the grid has buckets,
+// all points are scanned instead of the cells.
+func (self *T) ForEachNeighbour(p *vec2.T, radius float32, f func(index int, distanceSqr float32)) {
+	if len(self.points) == 0 || !(radius >= 0) {
+		return
+	}
+	radiusSqr := radius * radius
+	lo := self.Cell(&vec2.T{p[0] - radius, p[1] - radius})
+	hi := self.Cell(&vec2.T{p[0] + radius, p[1] + radius})
+	if numCells(&lo, &hi) > float64(len(self.start)-1) {
+		for i := range self.points {
+			d := vec2.Sub(&self.points[i], p)
+			if distanceSqr := d.LengthSqr(); distanceSqr <= radiusSqr {
+				f(i, distanceSqr)
+			}
+		}
+		return
+	}
+	// The loops count in int64, because the cell range may end at math.MaxInt32.
+	var c Cell
+	for y := int64(lo[1]); y <= int64(hi[1]); y++ {
+		c[1] = int32(y)
+		for x := int64(lo[0]); x <= int64(hi[0]); x++ {
+			c[0] = int32(x)
+			h := self.bucket(&c)
+			for _, i := range self.indices[self.start[h]:self.start[h+1]] {
+				q := &self.points[i]
+				// Skip points of other cells sharing the bucket,
+				// they are visited with their own cell.
+				if self.Cell(q) != c {
+					continue
+				}
+				d := vec2.Sub(q, p)
+				if distanceSqr := d.LengthSqr(); distanceSqr <= radiusSqr {
+					f(int(i), distanceSqr)
+				}
+			}
+		}
+	}
+}
+
+// Neighbours appends the indices of all points within radius of p
+// to result and returns it.
+func (self *T) Neighbours(p *vec2.T, radius float32, result []int) []int {
+	self.ForEachNeighbour(p, radius, func(index int, distanceSqr float32) {
+		result = append(result, index)
+	})
+	return result
+}
+
+// CellPoints appends the indices of all points in cell to result and returns it.
+func (self *T) CellPoints(cell Cell, result []int) []int {
+	if len(self.points) == 0 {
+		return result
+	}
+	h := self.bucket(&cell)
+	for _, i := range self.indices[self.start[h]:self.start[h+1]] {
+		if self.Cell(&self.points[i]) == cell {
+			result = append(result, int(i))
+		}
+	}
+	return result
+}
+
+// numCells returns the number of cells from lo to hi,
+// as float64 because it may exceed every integer type.
+func numCells(lo, hi *Cell) float64 {
+	n := 1.0
+	for i := range lo {
+		n *= float64(hi[i]) - float64(lo[i]) + 1
+	}
+	return n
+}
+
+func resizeInt32(s []int32, n int) []int32 {
+	if cap(s) < n {
+		return make([]int32, n)
+	}
+	s = s[:n]
+	for i := range s {
+		s[i] = 0
+	}
+	return s
+}
+
+// prefixSum turns the bucket counts in s[:len(s)-1]
+// into the end offsets of the buckets.
+func prefixSum(s []int32) {
+	var sum int32
+	for i := 0; i < len(s)-1; i++ {
+		sum += s[i]
+		s[i] = sum
+	}
+	s[len(s)-1] = sum
+}
+
+func parallel(n, workers int, f func(begin, end int)) {
+	var wg sync.WaitGroup
+	chunk := (n + workers - 1) / workers
+	for begin := 0; begin < n; begin += chunk {
+		end := begin + chunk
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		go func(begin, end int) {
+			defer wg.Done()
+			f(begin, end)
+		}(begin, end)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,121 @@
+package hashgrid3
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ungerik/go3d/vec3"
+)
+
+func randomPoints(r *rand.Rand, n int, min, max float32) []vec3.T {
+	points := make([]vec3.T, n)
+	for i := range points {
+		for j := range points[i] {
+			points[i][j] = min + float32(r.Float64())*(max-min)
+		}
+	}
+	return points
+}
+
+// bruteForce returns the sorted indices of all points within radius of p.
+func bruteForce(points []vec3.T, p *vec3.T, radius float32) []int {
+	result := []int{}
+	for i := range points {
+		d := vec3.Sub(&points[i], p)
+		if d.LengthSqr() <= radius*radius {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func checkNeighbours(t *testing.T, name string, grid *T, p *vec3.T, radius float32) {
+	result := grid.Neighbours(p, radius, []int{})
+	sort.Ints(result)
+	want := bruteForce(grid.Points(), p, radius)
+	if len(result) != len(want) {
+		t.Fatalf("%s: Neighbours(%v, %f) returned %d points, want %d", name, p, radius, len(result), len(want))
+	}
+	for i := range want {
+		if result[i] != want[i] {
+			t.Fatalf("%s: Neighbours(%v, %f) = %v, want %v", name, p, radius, result, want)
+		}
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 10, 1000} {
+		points := randomPoints(r, n, -10, 10)
+		grid, parallelGrid := New(1), New(1)
+		grid.Rebuild(points)
+		parallelGrid.RebuildParallel(points, 4)
+		for q := 0; q < 200; q++ {
+			p := randomPoints(r, 1, -12, 12)[0]
+			radius := float32(r.Float64() * 3)
+			checkNeighbours(t, "Rebuild", grid, &p, radius)
+			checkNeighbours(t, "RebuildParallel", parallelGrid, &p, radius)
+		}
+		// Large radii scan the points instead of the cells.
+		for _, radius := range []float32{30, 300, 1e10, float32(math.Inf(1))} {
+			checkNeighbours(t, "Rebuild", grid, &vec3.Zero, radius)
+		}
+	}
+}
+
+func TestNegativeCells(t *testing.T) {
+	grid := New(2)
+	points := []vec3.T{{-0.5, -2, -2.5}, {0.5, 2, 2.5}, {-1.5, -3.9, -2.1}}
+	grid.Rebuild(points)
+	if c := grid.Cell(&points[0]); c != (Cell{-1, -1, -2}) {
+		t.Errorf("Cell(%v) = %v, want [-1 -1 -2]", points[0], c)
+	}
+	if c := grid.Cell(&points[1]); c != (Cell{0, 1, 1}) {
+		t.Errorf("Cell(%v) = %v, want [0 1 1]", points[1], c)
+	}
+	if result := grid.CellPoints(Cell{-1, -1, -2}, nil); len(result) != 1 || result[0] != 0 {
+		t.Errorf("CellPoints([-1 -1 -2]) = %v, want [0]", result)
+	}
+	if result := grid.CellPoints(Cell{-1, -2, -2}, nil); len(result) != 1 || result[0] != 2 {
+		t.Errorf("CellPoints([-1 -2 -2]) = %v, want [2]", result)
+	}
+	checkNeighbours(t, "negative", grid, &vec3.T{-1, -3, -2}, 1.5)
+}
+
+func TestHugeCoordinates(t *testing.T) {
+	grid := New(1)
+	points := []vec3.T{{0, 0, 0}, {1, 1, 1}, {1e20, 0, 0}, {-1e20, -1e20, -1e20}}
+	grid.Rebuild(points)
+	if c := grid.Cell(&points[2]); c != (Cell{math.MaxInt32, 0, 0}) {
+		t.Errorf("Cell(%v) = %v, want it clamped to [%d 0 0]", points[2], c, math.MaxInt32)
+	}
+	if c := grid.Cell(&points[3]); c != (Cell{math.MinInt32, math.MinInt32, math.MinInt32}) {
+		t.Errorf("Cell(%v) = %v, want it clamped to math.MinInt32", points[3], c)
+	}
+	for _, radius := range []float32{0, 1, 2, 1e10, 1e30, float32(math.Inf(1))} {
+		for i := range points {
+			checkNeighbours(t, "huge", grid, &points[i], radius)
+		}
+	}
+	if result := grid.Neighbours(&vec3.Zero, -1, nil); len(result) != 0 {
+		t.Errorf("Neighbours with negative radius = %v, want none", result)
+	}
+}
+
+func TestRebuildAllocs(t *testing.T) {
+	points := randomPoints(rand.New(rand.NewSource(2)), 1000, -10, 10)
+	grid := New(1)
+	grid.Rebuild(points)
+	if allocs := testing.AllocsPerRun(10, func() { grid.Rebuild(points) }); allocs != 0 {
+		t.Errorf("Rebuild allocated %f times, want 0", allocs)
+	}
+	result := make([]int, 0, len(points))
+	allocs := testing.AllocsPerRun(10, func() {
+		result = grid.Neighbours(&points[0], 2, result[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("Neighbours allocated %f times, want 0", allocs)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestGeneratedFilesAreUpToDate(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The package paths are relative to the root of the repository.
+	if err = os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for _, pkg := range packages {
+		stale, err := generatePackage(pkg, true)
+		if err != nil {
+			t.Fatalf("%s: %v", pkg, err)
+		}
+		if stale {
+			t.Errorf("%sd has stale or orphaned generated files, run go generate", pkg)
+		}
+	}
+}
+
+func TestTargetName(t *testing.T) {
+	tests := map[string]string{
+		"vec3.go":       "vec3d.go",
+		"box.go":        "boxd.go",
+		"eigen_test.go": "eigend_test.go",
+	}
+	for source, want := range tests {
+		if got := targetName(source); got != want {
+			t.Errorf("targetName(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+// Command gend generates the float64 packages of go3d from their float32 sources.
+//
+// Every file pkg/name.go of the float32 packages listed below
+// is translated to pkgd/named.go by replacing float32 with float64,
+// test files pkg/name_test.go are translated to pkgd/named_test.go,
+// the imported go3d packages with their float64 twins and
+// github.com/barnex/fmath with the standard math package.
+//
+// Literals that need a different value for float64, like epsilons,
+// are marked with a trailing directive comment in the float32 source:
+//
+//	const epsilon = 1e-5 //gend:float64 1e-10
+//
+// Files of the float64 packages without the generated header are not touched,
+// so code that only makes sense for one precision can be added by hand.
+//
+// Run it from the root of the repository with
+//
+//	go generate
+//
+// or check that the generated files are up to date with
+//
+//	go run ./internal/gend -check
+//
+// The same check is run by go test.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	modulePath = "github.com/ungerik/go3d"
+	fmathPath  = "github.com/barnex/fmath"
+	directive  = "//gend:float64 "
+)
+
+// packages are the float32 packages with generated float64 twins.
+var packages = []string{
+	"bvh",
+	"frustum",
+	"generic",
+	"gjk",
+	"hermit",
+	"kdtree",
+	"mat2x2",
+	"mat3x3",
+	"mat4x4",
+	"matn",
+	"obb",
+	"octree",
+	"quadtree",
+	"quaternion",
+	"vec2",
+	"vec3",
+	"vec4",
+}
+
+var (
+	isPackage = make(map[string]bool)
+
+	// packageRefRegexp matches references like vec3.T in comments.
+	packageRefRegexp *regexp.Regexp
+)
+
+func init() {
+	for _, pkg := range packages {
+		isPackage[pkg] = true
+	}
+	packageRefRegexp = regexp.MustCompile(`\b(` + strings.Join(packages, "|") + `)\.([A-Za-z_])`)
+}
+
+func main() {
+	check := flag.Bool("check", false, "only report stale generated files and exit with status 1 if there are any")
+	flag.Parse()
+
+	stale := false
+	for _, pkg := range packages {
+		s, err := generatePackage(pkg, *check)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "gend:", err)
+			os.Exit(2)
+		}
+		stale = stale || s
+	}
+	if stale && *check {
+		fmt.Fprintln(os.Stderr, "gend: generated files are stale, run go generate")
+		os.Exit(1)
+	}
+}
+
+// generatePackage generates the float64 twin of pkg and returns if
+// any file was stale. If check is true, no files are written or removed.
+func generatePackage(pkg string, check bool) (stale bool, err error) {
+	sources, err := filepath.Glob(filepath.Join(pkg, "*.go"))
+	if err != nil {
+		return false, err
+	}
+	targetDir := pkg + "d"
+	targets := make(map[string]bool)
+	for _, source := range sources {
+		output, err := generateFile(source)
+		if err != nil {
+			return false, err
+		}
+		target := filepath.Join(targetDir, targetName(filepath.Base(source)))
+		targets[target] = true
+		existing, err := ioutil.ReadFile(target)
+		if err == nil && bytes.Equal(existing, output) {
+			continue
+		}
+		stale = true
+		if check {
+			fmt.Fprintln(os.Stderr, "stale:", target)
+			continue
+		}
+		if err = os.MkdirAll(targetDir, 0755); err != nil {
+			return false, err
+		}
+		if err = ioutil.WriteFile(target, output, 0644); err != nil {
+			return false, err
+		}
+	}
+
+	// Remove generated files whose source is gone.
+	existing, err := filepath.Glob(filepath.Join(targetDir, "*.go"))
+	if err != nil {
+		return false, err
+	}
+	for _, target := range existing {
+		if targets[target] || !isGenerated(target) {
+			continue
+		}
+		stale = true
+		if check {
+			fmt.Fprintln(os.Stderr, "orphaned:", target)
+			continue
+		}
+		if err = os.Remove(target); err != nil {
+			return false, err
+		}
+	}
+	return stale, nil
+}
+
+// targetName returns the name of the generated file for the source file name.
+func targetName(name string) string {
+	if strings.HasSuffix(name, "_test.go") {
+		return strings.TrimSuffix(name, "_test.go") + "d_test.go"
+	}
+	return strings.TrimSuffix(name, ".go") + "d.go"
+}
+
+func isGenerated(filename string) bool {
+	data, err := ioutil.ReadFile(filename)
+	return err == nil && bytes.HasPrefix(data, []byte("// Code generated by gend"))
+}
+
+type edit struct {
+	pos, end int
+	text     string
+}
+
+// generateFile returns the float64 translation of the float32 source file.
+func generateFile(filename string) ([]byte, error) {
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	var edits []edit
+	replace := func(node ast.Node, text string) {
+		edits = append(edits, edit{offset(node.Pos()), offset(node.End()), text})
+	}
+
+	// External test packages keep their _test suffix.
+	pkg := strings.TrimSuffix(file.Name.Name, "_test")
+	replace(file.Name, pkg+"d"+strings.TrimPrefix(file.Name.Name, pkg))
+
+	// renamed maps the local names of imported packages to their new names.
+	renamed := make(map[string]string)
+	var imports []*ast.GenDecl
+	for _, decl := range file.Decls {
+		if d, ok := decl.(*ast.GenDecl); ok && d.Tok == token.IMPORT {
+			imports = append(imports, d)
+		}
+	}
+	if len(imports) > 0 {
+		text, err := translateImports(imports, renamed)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+		edits = append(edits, edit{offset(imports[0].Pos()), offset(imports[0].End()), text})
+		for _, d := range imports[1:] {
+			replace(d, "")
+		}
+	}
+
+	// Translate the code outside of the import declarations.
+	literals := make(map[int]*ast.BasicLit)
+	for _, decl := range file.Decls {
+		if d, ok := decl.(*ast.GenDecl); ok && d.Tok == token.IMPORT {
+			continue
+		}
+		ast.Inspect(decl, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.SelectorExpr:
+				// Unresolved identifiers in front of a selector are package names.
+				if x, ok := n.X.(*ast.Ident); ok && x.Obj == nil {
+					name := x.Name
+					if newName, ok := renamed[name]; ok {
+						replace(x, newName)
+						name = newName
+					}
+					if name == "math" && strings.HasSuffix(n.Sel.Name, "Float32") {
+						replace(n.Sel, strings.TrimSuffix(n.Sel.Name, "Float32")+"Float64")
+					}
+				}
+			case *ast.Ident:
+				if n.Name == "float32" && n.Obj == nil {
+					replace(n, "float64")
+				}
+			case *ast.BasicLit:
+				switch n.Kind {
+				case token.FLOAT, token.INT:
+					line := fset.Position(n.Pos()).Line
+					if _, ok := literals[line]; ok {
+						// Lines with multiple literals are ambiguous for directives.
+						literals[line] = nil
+					} else {
+						literals[line] = n
+					}
+				case token.STRING:
+					// Error messages are prefixed with the package name.
+					if strings.HasPrefix(n.Value, `"`+pkg+":") {
+						replace(n, `"`+pkg+"d"+n.Value[len(pkg)+1:])
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	// Translate the comments and apply the directives.
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if strings.HasPrefix(comment.Text, directive) {
+				line := fset.Position(comment.Pos()).Line
+				literal := literals[line]
+				if literal == nil {
+					return nil, fmt.Errorf("%s:%d: no single literal for %s", filename, line, strings.TrimSpace(directive))
+				}
+				replace(literal, strings.TrimSpace(strings.TrimPrefix(comment.Text, directive)))
+				start := offset(comment.Pos())
+				for start > 0 && (src[start-1] == ' ' || src[start-1] == '\t') {
+					start--
+				}
+				edits = append(edits, edit{start, offset(comment.End()), ""})
+				continue
+			}
+			if text := translateComment(comment.Text, pkg); text != comment.Text {
+				replace(comment, text)
+			}
+		}
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].pos > edits[j].pos })
+	for i := 1; i < len(edits); i++ {
+		if edits[i].end > edits[i-1].pos {
+			return nil, fmt.Errorf("%s: overlapping edits at offset %d", filename, edits[i].pos)
+		}
+	}
+	out := src
+	for _, e := range edits {
+		out = append(out[:e.pos:e.pos], append([]byte(e.text), out[e.end:]...)...)
+	}
+
+	header := fmt.Sprintf("// Code generated by gend from %s. DO NOT EDIT.\n\n", filepath.ToSlash(filename))
+	out = append([]byte(header), out...)
+	formatted, err := format.Source(out)
+	if err != nil {
+		return nil, fmt.Errorf("%s: formatting output: %v", filename, err)
+	}
+	return formatted, nil
+}
+
+// translateImports returns the import declaration of the float64 package
+// and fills renamed with the new local names of the imported packages.
+func translateImports(decls []*ast.GenDecl, renamed map[string]string) (string, error) {
+	seen := make(map[string]bool)
+	var std, other []string
+	add := func(name, importPath string) {
+		spec := strconv.Quote(importPath)
+		if name != "" {
+			spec = name + " " + spec
+		}
+		if seen[spec] {
+			return
+		}
+		seen[spec] = true
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	parens := false
+	for _, decl := range decls {
+		parens = parens || decl.Lparen.IsValid()
+		for _, s := range decl.Specs {
+			spec := s.(*ast.ImportSpec)
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				return "", err
+			}
+			name := ""
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			localName := name
+			if localName == "" {
+				localName = path.Base(importPath)
+			}
+			switch {
+			case importPath == fmathPath:
+				if name != "" {
+					return "", fmt.Errorf("renamed import of %s is not supported", fmathPath)
+				}
+				renamed[localName] = "math"
+				add("", "math")
+			case strings.HasPrefix(importPath, modulePath+"/") && isPackage[strings.TrimPrefix(importPath, modulePath+"/")]:
+				if name == "" {
+					renamed[localName] = localName + "d"
+				}
+				add(name, importPath+"d")
+			default:
+				add(name, importPath)
+			}
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	specs := std
+	if len(std) > 0 && len(other) > 0 {
+		specs = append(specs, "")
+	}
+	specs = append(specs, other...)
+	if len(specs) == 1 && !parens {
+		return "import " + specs[0], nil
+	}
+	return "import (\n\t" + strings.Join(specs, "\n\t") + "\n)", nil
+}
+
+// translateComment replaces the float32 types and package names in a comment.
+func translateComment(text, pkg string) string {
+	text = strings.Replace(text, "float32", "float64", -1)
+	text = packageRefRegexp.ReplaceAllString(text, "${1}d.$2")
+	return regexp.MustCompile(`\b([Pp]ackage) `+pkg+`\b`).ReplaceAllString(text, "$1 "+pkg+"d")
+}
@@ -0,0 +1,285 @@
+// The package kdtree contains a static float32 KD-tree
+// for nearest neighbour searches in point clouds.
+//
+// The tree is built once in O(n log n) and stored implicitly
+// as a permutation of the point indices, where the median
+// of every index range splits it along the stored axis.
+// Queries don't modify the tree, so a T is safe
+// for concurrent queries from multiple goroutines.
+package kdtree
+
+import (
+	"fmt"
+
+	"github.com/ungerik/go3d/vec3"
+)
+
+// T is a KD-tree over a slice of points.
+type T struct {
+	points  []vec3.T
+	indices []int32
+	// axes holds the split axis of every node at the index of its median.
+	axes []uint8
+}
+
+// New builds a KD-tree over points.
+// The tree references points, so they must not be modified while it is used.
+func New(points []vec3.T) *T {
+	self := &T{
+		points:  points,
+		indices: make([]int32, len(points)),
+		axes:    make([]uint8, len(points)),
+	}
+	for i := range self.indices {
+		self.indices[i] = int32(i)
+	}
+	self.build(0, len(points))
+	return self
+}
+
+// String returns a description of the tree.
+func (self *T) String() string {
+	return fmt.Sprintf("kdtree with %d points", len(self.points))
+}
+
+// Len returns the number of points.
+func (self *T) Len() int {
+	return len(self.points)
+}
+
+// Points returns the points the tree was built from.
+func (self *T) Points() []vec3.T {
+	return self.points
+}
+
+func (self *T) build(lo, hi int) {
+	for hi-lo > 1 {
+		bounds := vec3.EmptyBox
+		for _, i := range self.indices[lo:hi] {
+			bounds.ExtendByPoint(&self.points[i])
+		}
+		size := bounds.Size()
+		axis := 0
+		if size[1] > size[axis] {
+			axis = 1
+		}
+		if size[2] > size[axis] {
+			axis = 2
+		}
+		mid := (lo + hi) / 2
+		self.selectNth(lo, hi, mid, axis)
+		self.axes[mid] = uint8(axis)
+		self.build(lo, mid)
+		lo = mid + 1
+	}
+}
+
+// selectNth partially sorts indices[lo:hi] along axis
+// so that the element at n is the one a full sort would put there.
+func (self *T) selectNth(lo, hi, n, axis int) {
+	indices := self.indices
+	hi--
+	for hi > lo {
+		// Median of three pivot
+		mid := (lo + hi) / 2
+		if self.coord(mid, axis) < self.coord(lo, axis) {
+			indices[lo], indices[mid] = indices[mid], indices[lo]
+		}
+		if self.coord(hi, axis) < self.coord(lo, axis) {
+			indices[lo], indices[hi] = indices[hi], indices[lo]
+		}
+		if self.coord(hi, axis) < self.coord(mid, axis) {
+			indices[mid], indices[hi] = indices[hi], indices[mid]
+		}
+		pivot := self.coord(mid, axis)
+
+		i, j := lo, hi
+		for i <= j {
+			for self.coord(i, axis) < pivot {
+				i++
+			}
+			for self.coord(j, axis) > pivot {
+				j--
+			}
+			if i <= j {
+				indices[i], indices[j] = indices[j], indices[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case n <= j:
+			hi = j
+		case n >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+func (self *T) coord(i, axis int) float32 {
+	return self.points[self.indices[i]][axis]
+}
+
+// Nearest returns the index of the point nearest to point
+// and the squared distance to it. index is -1 if the tree is empty.
+func (self *T) Nearest(point *vec3.T) (index int, distanceSqr float32) {
+	return self.ApproxNearest(point, 0)
+}
+
+// ApproxNearest returns the index of a point whose distance to point
+// is at most (1 + eps) times the distance of the nearest point,
+// and the squared distance to it. Larger values of eps visit fewer nodes.
+// index is -1 if the tree is empty.
+func (self *T) ApproxNearest(point *vec3.T, eps float32) (index int, distanceSqr float32) {
+	var mem [1]neighbour
+	s := search{tree: self, point: point, k: 1, heap: mem[:0], scale: (1 + eps) * (1 + eps)}
+	s.run(0, len(self.points))
+	if len(s.heap) == 0 {
+		return -1, 0
+	}
+	return int(s.heap[0].index), s.heap[0].distanceSqr
+}
+
+// KNearest appends the indices of the k points nearest to point
+// to result, ordered by increasing distance, and returns it.
+func (self *T) KNearest(point *vec3.T, k int, result []int) []int {
+	return self.ApproxKNearest(point, k, 0, result)
+}
+
+// ApproxKNearest is like KNearest, but the distance of the i-th returned point
+// is only guaranteed to be at most (1 + eps) times the distance
+// of the true i-th nearest point.
+func (self *T) ApproxKNearest(point *vec3.T, k int, eps float32, result []int) []int {
+	if k <= 0 {
+		return result
+	}
+	s := search{tree: self, point: point, k: k, scale: (1 + eps) * (1 + eps)}
+	s.run(0, len(self.points))
+	// Pop the max-heap from the back to get increasing distances.
+	start := len(result)
+	for i := len(s.heap); i > 0; i-- {
+		result = append(result, 0)
+	}
+	for i := len(result) - 1; i >= start; i-- {
+		result[i] = int(s.heap[0].index)
+		s.pop()
+	}
+	return result
+}
+
+// Radius appends the indices of all points within radius of point
+// to result in no particular order and returns it.
+func (self *T) Radius(point *vec3.T, radius float32, result []int) []int {
+	return self.radius(point, radius*radius, 0, len(self.points), result)
+}
+
+func (self *T) radius(point *vec3.T, radiusSqr float32, lo, hi int, result []int) []int {
+	for lo < hi {
+		mid := (lo + hi) / 2
+		index := self.indices[mid]
+		d := vec3.Sub(point, &self.points[index])
+		if d.LengthSqr() <= radiusSqr {
+			result = append(result, int(index))
+		}
+		axis := self.axes[mid]
+		diff := d[axis]
+		if diff <= 0 || diff*diff <= radiusSqr {
+			result = self.radius(point, radiusSqr, lo, mid, result)
+		}
+		if diff >= 0 || diff*diff <= radiusSqr {
+			lo = mid + 1
+		} else {
+			return result
+		}
+	}
+	return result
+}
+
+type neighbour struct {
+	index       int32
+	distanceSqr float32
+}
+
+// search holds the state of a k nearest neighbour search
+// with the found neighbours in a max-heap.
+type search struct {
+	tree  *T
+	point *vec3.T
+	k     int
+	heap  []neighbour
+	// scale is (1 + eps)^2 of approximate searches.
+	scale float32
+}
+
+func (self *search) run(lo, hi int) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	index := self.tree.indices[mid]
+	d := vec3.Sub(self.point, &self.tree.points[index])
+	self.add(index, d.LengthSqr())
+
+	diff := d[self.tree.axes[mid]]
+	if diff < 0 {
+		self.run(lo, mid)
+		if len(self.heap) < self.k || diff*diff*self.scale < self.heap[0].distanceSqr {
+			self.run(mid+1, hi)
+		}
+	} else {
+		self.run(mid+1, hi)
+		if len(self.heap) < self.k || diff*diff*self.scale < self.heap[0].distanceSqr {
+			self.run(lo, mid)
+		}
+	}
+}
+
+func (self *search) add(index int32, distanceSqr float32) {
+	if len(self.heap) < self.k {
+		self.heap = append(self.heap, neighbour{index, distanceSqr})
+		// Sift up
+		i := len(self.heap) - 1
+		for i > 0 {
+			parent := (i - 1) / 2
+			if self.heap[parent].distanceSqr >= self.heap[i].distanceSqr {
+				break
+			}
+			self.heap[parent], self.heap[i] = self.heap[i], self.heap[parent]
+			i = parent
+		}
+		return
+	}
+	if distanceSqr >= self.heap[0].distanceSqr {
+		return
+	}
+	self.heap[0] = neighbour{index, distanceSqr}
+	self.siftDown()
+}
+
+func (self *search) pop() {
+	last := len(self.heap) - 1
+	self.heap[0] = self.heap[last]
+	self.heap = self.heap[:last]
+	self.siftDown()
+}
+
+func (self *search) siftDown() {
+	i := 0
+	for {
+		largest := i
+		left, right := 2*i+1, 2*i+2
+		if left < len(self.heap) && self.heap[left].distanceSqr > self.heap[largest].distanceSqr {
+			largest = left
+		}
+		if right < len(self.heap) && self.heap[right].distanceSqr > self.heap[largest].distanceSqr {
+			largest = right
+		}
+		if largest == i {
+			return
+		}
+		self.heap[i], self.heap[largest] = self.heap[largest], self.heap[i]
+		i = largest
+	}
+}
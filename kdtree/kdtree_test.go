@@ -0,0 +1,224 @@
+package kdtree
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ungerik/go3d/vec3"
+)
+
+// distanceEpsilon is the relative tolerance of compared squared distances.
+const distanceEpsilon = 1e-5 //gend:float64 1e-12
+
+func randomPoints(r *rand.Rand, n int) []vec3.T {
+	points := make([]vec3.T, n)
+	for i := range points {
+		points[i] = vec3.T{float32(r.Float64()), float32(r.Float64()), float32(r.Float64())}
+	}
+	return points
+}
+
+// randomCloud returns random points with some duplicates
+// and points that share coordinates with other points.
+func randomCloud(r *rand.Rand, n int) []vec3.T {
+	points := randomPoints(r, n)
+	for i := range points {
+		switch r.Intn(8) {
+		case 0:
+			points[i] = points[r.Intn(i+1)]
+		case 1:
+			points[i][r.Intn(3)] = points[r.Intn(i+1)][r.Intn(3)]
+		}
+	}
+	return points
+}
+
+func distanceSqr(a, b *vec3.T) float32 {
+	d := vec3.Sub(a, b)
+	return d.LengthSqr()
+}
+
+// sortedDistances returns the squared distances of all points to p in increasing order.
+func sortedDistances(points []vec3.T, p *vec3.T) []float32 {
+	distances := make([]float32, len(points))
+	for i := range points {
+		distances[i] = distanceSqr(&points[i], p)
+	}
+	sort.Slice(distances, func(i, j int) bool { return distances[i] < distances[j] })
+	return distances
+}
+
+func TestNearest(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 10, 1000} {
+		points := randomCloud(r, n)
+		tree := New(points)
+		for q := 0; q < 200; q++ {
+			p := vec3.T{float32(r.Float64()*1.4 - 0.2), float32(r.Float64()*1.4 - 0.2), float32(r.Float64()*1.4 - 0.2)}
+			index, dist := tree.Nearest(&p)
+			if n == 0 {
+				if index != -1 {
+					t.Fatalf("Nearest of empty tree = %d, want -1", index)
+				}
+				continue
+			}
+			want := sortedDistances(points, &p)[0]
+			if dist != want || distanceSqr(&points[index], &p) != want {
+				t.Fatalf("Nearest(%v) = %d, %f, want distance %f", p, index, dist, want)
+			}
+
+			for _, eps := range []float32{0.1, 0.5, 2} {
+				index, dist := tree.ApproxNearest(&p, eps)
+				bound := want * (1 + eps) * (1 + eps) * (1 + distanceEpsilon)
+				if dist > bound || distanceSqr(&points[index], &p) != dist {
+					t.Fatalf("ApproxNearest(%v, %f) = %d, %f, want at most %f", p, eps, index, dist, bound)
+				}
+			}
+		}
+	}
+}
+
+func TestKNearest(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	points := randomCloud(r, 2000)
+	tree := New(points)
+	var result []int
+	for q := 0; q < 200; q++ {
+		p := vec3.T{float32(r.Float64()), float32(r.Float64()), float32(r.Float64())}
+		want := sortedDistances(points, &p)
+		for _, k := range []int{1, 5, 50} {
+			result = tree.KNearest(&p, k, result[:0])
+			if len(result) != k {
+				t.Fatalf("KNearest(%v, %d) returned %d points", p, k, len(result))
+			}
+			seen := make(map[int]bool)
+			for i, index := range result {
+				if seen[index] {
+					t.Fatalf("KNearest(%v, %d) returned %d twice", p, k, index)
+				}
+				seen[index] = true
+				if d := distanceSqr(&points[index], &p); d != want[i] {
+					t.Fatalf("KNearest(%v, %d)[%d] has distance %f, want %f", p, k, i, d, want[i])
+				}
+			}
+
+			eps := float32(0.5)
+			result = tree.ApproxKNearest(&p, k, eps, result[:0])
+			for i, index := range result {
+				bound := want[i] * (1 + eps) * (1 + eps) * (1 + distanceEpsilon)
+				if d := distanceSqr(&points[index], &p); d > bound {
+					t.Fatalf("ApproxKNearest(%v, %d)[%d] has distance %f, want at most %f", p, k, i, d, bound)
+				}
+			}
+		}
+	}
+	if result = tree.KNearest(&points[0], len(points)+10, result[:0]); len(result) != len(points) {
+		t.Errorf("KNearest with k larger than the tree returned %d points, want %d", len(result), len(points))
+	}
+}
+
+func TestRadius(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	points := randomCloud(r, 2000)
+	tree := New(points)
+	var result []int
+	for q := 0; q < 200; q++ {
+		p := vec3.T{float32(r.Float64()), float32(r.Float64()), float32(r.Float64())}
+		radius := float32(r.Float64() * 0.2)
+		result = tree.Radius(&p, radius, result[:0])
+		var want []int
+		for i := range points {
+			if distanceSqr(&points[i], &p) <= radius*radius {
+				want = append(want, i)
+			}
+		}
+		sort.Ints(result)
+		if len(result) != len(want) {
+			t.Fatalf("Radius(%v, %f) returned %d points, want %d", p, radius, len(result), len(want))
+		}
+		for i := range want {
+			if result[i] != want[i] {
+				t.Fatalf("Radius(%v, %f) = %v, want %v", p, radius, result, want)
+			}
+		}
+	}
+}
+
+// TestConcurrentQueries is meant to be run with go test -race.
+func TestConcurrentQueries(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	points := randomPoints(r, 5000)
+	tree := New(points)
+	queries := randomPoints(r, 200)
+	want := make([]float32, len(queries))
+	for i := range queries {
+		_, want[i] = tree.Nearest(&queries[i])
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var result []int
+			for i := range queries {
+				if _, d := tree.Nearest(&queries[i]); d != want[i] {
+					t.Errorf("concurrent Nearest(%v) = %f, want %f", queries[i], d, want[i])
+				}
+				tree.ApproxNearest(&queries[i], 0.5)
+				result = tree.KNearest(&queries[i], 10, result[:0])
+				result = tree.Radius(&queries[i], 0.05, result[:0])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+const benchmarkPoints = 1000000
+
+var (
+	benchmarkOnce  sync.Once
+	benchmarkCloud []vec3.T
+	benchmarkTree  *T
+)
+
+func benchmarkSetup(b *testing.B) ([]vec3.T, *T) {
+	benchmarkOnce.Do(func() {
+		benchmarkCloud = randomPoints(rand.New(rand.NewSource(1)), benchmarkPoints)
+		benchmarkTree = New(benchmarkCloud)
+	})
+	b.ResetTimer()
+	return benchmarkCloud, benchmarkTree
+}
+
+func BenchmarkNew1M(b *testing.B) {
+	points, _ := benchmarkSetup(b)
+	for i := 0; i < b.N; i++ {
+		New(points)
+	}
+}
+
+func BenchmarkNearest1M(b *testing.B) {
+	points, tree := benchmarkSetup(b)
+	for i := 0; i < b.N; i++ {
+		tree.Nearest(&points[(i*7919)%len(points)])
+	}
+}
+
+func BenchmarkKNearest1M(b *testing.B) {
+	points, tree := benchmarkSetup(b)
+	var result []int
+	for i := 0; i < b.N; i++ {
+		result = tree.KNearest(&points[(i*7919)%len(points)], 16, result[:0])
+	}
+}
+
+func BenchmarkRadius1M(b *testing.B) {
+	points, tree := benchmarkSetup(b)
+	var result []int
+	for i := 0; i < b.N; i++ {
+		result = tree.Radius(&points[(i*7919)%len(points)], 0.01, result[:0])
+	}
+}
@@ -1,12 +1,17 @@
 package mat2x2
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ungerik/go3d/generic"
 	"github.com/ungerik/go3d/vec2"
 )
 
+// ErrSingular is returned when a singular matrix
+// that has no inverse should be inverted.
+var ErrSingular = errors.New("mat2x2: matrix is singular")
+
 var (
 	Zero  = T{}
 	Ident = T{
@@ -79,6 +84,11 @@ func (self *T) Get(col, row int) float32 {
 	return self[col][row]
 }
 
+// Set sets one element of the matrix.
+func (self *T) Set(col, row int, value float32) {
+	self[col][row] = value
+}
+
 // IsZero checks if all elements of the matrix are zero.
 func (self *T) IsZero() bool {
 	return *self == Zero
@@ -110,6 +120,60 @@ func (self *T) AssignMul(a, b *T) *T {
 func (self *T) MulVec2(vec *vec2.T) vec2.T {
 	return vec2.T{
 		self[0][0]*vec[0] + self[1][0]*vec[1],
-		self[0][1]*vec[1] + self[1][1]*vec[1],
+		self[0][1]*vec[0] + self[1][1]*vec[1],
+	}
+}
+
+func (self *T) Determinant() float32 {
+	return self[0][0]*self[1][1] - self[1][0]*self[0][1]
+}
+
+// Adjugate replaces the matrix with its adjugate,
+// the transpose of its cofactor matrix, and returns self.
+func (self *T) Adjugate() *T {
+	*self = self.Adjugated()
+	return self
+}
+
+// Adjugated returns the adjugate of the matrix,
+// the transpose of its cofactor matrix.
+func (self *T) Adjugated() T {
+	return T{
+		vec2.T{self[1][1], -self[0][1]},
+		vec2.T{-self[1][0], self[0][0]},
+	}
+}
+
+// Invert inverts the matrix.
+// If the matrix is singular ErrSingular is returned and the matrix is not modified.
+func (self *T) Invert() error {
+	det := self.Determinant()
+	if det == 0 {
+		return ErrSingular
+	}
+	self.Adjugate()
+	ooDet := 1 / det
+	self[0].Scale(ooDet)
+	self[1].Scale(ooDet)
+	return nil
+}
+
+// Inverted returns an inverted copy of the matrix.
+// If the matrix is singular ErrSingular is returned.
+func (self *T) Inverted() (T, error) {
+	r := *self
+	err := r.Invert()
+	return r, err
+}
+
+// Solve returns x for the linear equation system self * x = b.
+// If the matrix is singular ErrSingular is returned.
+func (self *T) Solve(b *vec2.T) (vec2.T, error) {
+	det := self.Determinant()
+	if det == 0 {
+		return vec2.Zero, ErrSingular
 	}
+	adj := self.Adjugated()
+	x := adj.MulVec2(b)
+	return *x.Scale(1 / det), nil
 }
@@ -0,0 +1,153 @@
+package mat3x3
+
+import (
+	"github.com/barnex/fmath"
+	"github.com/ungerik/go3d/vec3"
+)
+
+const (
+	// jacobiEpsilon is the squared relative size of the off-diagonal
+	// elements at which the Jacobi iteration stops.
+	jacobiEpsilon = 1e-14 //gend:float64 1e-30
+
+	// svdEpsilon is the relative size below which singular vectors
+	// are replaced by arbitrary orthonormal ones.
+	svdEpsilon = 1e-6 //gend:float64 1e-14
+
+	// maxJacobiSweeps limits the Jacobi iteration.
+	maxJacobiSweeps = 50
+)
+
+// SymmetricEigen returns the eigenvalues of the symmetric matrix
+// in decreasing order and the corresponding eigenvectors as columns
+// of a rotation matrix. Only the lower triangle of the matrix is read.
+// The eigenvectors of repeated eigenvalues are an arbitrary orthonormal
+// basis of their eigenspace.
+func (self *T) SymmetricEigen() (values vec3.T, vectors T) {
+	a := *self
+	// Mirror the lower triangle
+	a[1][0], a[2][0], a[2][1] = a[0][1], a[0][2], a[1][2]
+	v := Ident
+
+	var norm float32
+	for col := 0; col < 3; col++ {
+		norm += a[col].LengthSqr()
+	}
+	for sweep := 0; sweep < maxJacobiSweeps; sweep++ {
+		off := a[1][0]*a[1][0] + a[2][0]*a[2][0] + a[2][1]*a[2][1]
+		if off <= jacobiEpsilon*norm {
+			break
+		}
+		for p := 0; p < 2; p++ {
+			for q := p + 1; q < 3; q++ {
+				if a[q][p] == 0 {
+					continue
+				}
+				// Rotate rows and columns p and q so that a[q][p] becomes zero.
+				theta := (a[q][q] - a[p][p]) / (2 * a[q][p])
+				t := 1 / (fmath.Abs(theta) + fmath.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / fmath.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < 3; k++ {
+					akp, akq := a[p][k], a[q][k]
+					a[p][k] = c*akp - s*akq
+					a[q][k] = s*akp + c*akq
+				}
+				for k := 0; k < 3; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < 3; k++ {
+					vp, vq := v[p][k], v[q][k]
+					v[p][k] = c*vp - s*vq
+					v[q][k] = s*vp + c*vq
+				}
+			}
+		}
+	}
+
+	values = vec3.T{a[0][0], a[1][1], a[2][2]}
+	// Sort by decreasing eigenvalues
+	for i := 0; i < 2; i++ {
+		for j := i + 1; j < 3; j++ {
+			if values[j] > values[i] {
+				values[i], values[j] = values[j], values[i]
+				v[i], v[j] = v[j], v[i]
+			}
+		}
+	}
+	if v.IsReflective() {
+		v[2].Invert()
+	}
+	return values, v
+}
+
+// SVD returns the singular value decomposition self = U * diag(sigma) * V^T
+// with the singular values sorted by decreasing magnitude.
+// If rotations is false, the singular values are not negative and
+// U or V may contain a reflection. If rotations is true, U and V are
+// rotation matrices and the last singular value is negative
+// if the matrix is reflective.
+func (self *T) SVD(rotations bool) (u T, sigma vec3.T, v T) {
+	// The right singular vectors are the eigenvectors of self^T * self.
+	var ata T
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			ata[col][row] = vec3.Dot(&self[row], &self[col])
+		}
+	}
+	_, v = ata.SymmetricEigen()
+
+	// Orthonormalize the columns of self * V to get U.
+	var b T
+	for i := 0; i < 3; i++ {
+		b[i] = self.MulVec3(&v[i])
+	}
+	// The length of b[0] is the largest singular value.
+	tolerance := b[0].Length() * svdEpsilon
+
+	u[0] = b[0]
+	if u[0].Length() <= tolerance {
+		u[0] = vec3.UnitX
+	}
+	u[0].Normalize()
+
+	u[1] = b[1]
+	proj := u[0].Scaled(vec3.Dot(&u[0], &u[1]))
+	u[1].Sub(&proj)
+	if u[1].Length() <= tolerance {
+		u[1] = perpendicular(&u[0])
+	}
+	u[1].Normalize()
+
+	u[2] = vec3.Cross(&u[0], &u[1])
+
+	for i := 0; i < 3; i++ {
+		sigma[i] = vec3.Dot(&u[i], &b[i])
+	}
+	if !rotations && sigma[2] < 0 {
+		sigma[2] = -sigma[2]
+		u[2].Invert()
+	}
+	return u, sigma, v
+}
+
+// perpendicular returns a unit vector perpendicular to the unit vector v.
+func perpendicular(v *vec3.T) vec3.T {
+	x, y, z := fmath.Abs(v[0]), fmath.Abs(v[1]), fmath.Abs(v[2])
+	var axis vec3.T
+	switch {
+	case x <= y && x <= z:
+		axis = vec3.UnitX
+	case y <= z:
+		axis = vec3.UnitY
+	default:
+		axis = vec3.UnitZ
+	}
+	p := vec3.Cross(v, &axis)
+	return p.Normalized()
+}
@@ -0,0 +1,170 @@
+package mat3x3
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/barnex/fmath"
+	"github.com/ungerik/go3d/vec3"
+)
+
+// testEpsilon is the tolerance of the results relative to the input size.
+const testEpsilon = 1e-4 //gend:float64 1e-10
+
+var eigenTests = []struct {
+	name string
+	m    T
+	want vec3.T
+}{
+	{"zero", T{}, vec3.T{0, 0, 0}},
+	{"identity", Ident, vec3.T{1, 1, 1}},
+	{"repeated", T{{2, 1, 0}, {1, 2, 0}, {0, 0, 3}}, vec3.T{3, 3, 1}},
+	{"repeated diagonal", T{{2, 0, 0}, {0, 1, 0}, {0, 0, 2}}, vec3.T{2, 2, 1}},
+	{"rank 1", T{{1, 2, 3}, {2, 4, 6}, {3, 6, 9}}, vec3.T{14, 0, 0}},
+	{"negative", T{{-1, 0, 0}, {0, 4, 2}, {0, 2, 1}}, vec3.T{5, 0, -1}},
+}
+
+var svdTests = []struct {
+	name       string
+	m          T
+	reflective bool
+}{
+	{"zero", T{}, false},
+	{"identity", Ident, false},
+	{"repeated", T{{2, 1, 0}, {1, 2, 0}, {0, 0, 3}}, false},
+	{"rank 1", T{{1, 2, 3}, {2, 4, 6}, {3, 6, 9}}, false},
+	{"rank 2", T{{1, 2, 3}, {2, 4, 6}, {1, 0, 0}}, false},
+	{"mirror", T{{-1, 0, 0}, {0, 2, 0}, {0, 0, 3}}, true},
+	{"swap", T{{0, 1, 0}, {1, 0, 0}, {0, 0, 1}}, true},
+	{"reflective", T{{1, 2, 0}, {0, 1, 3}, {-2, 0, 1}}, true},
+}
+
+func size(m *T) float32 {
+	return fmath.Sqrt(m[0].LengthSqr() + m[1].LengthSqr() + m[2].LengthSqr())
+}
+
+func distance(a, b *vec3.T) float32 {
+	d := vec3.Sub(a, b)
+	return d.Length()
+}
+
+func checkOrthonormal(t *testing.T, name string, m *T) {
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			want := float32(0)
+			if i == j {
+				want = 1
+			}
+			if d := vec3.Dot(&m[i], &m[j]); fmath.Abs(d-want) > testEpsilon {
+				t.Errorf("%s: columns %d and %d of %v are not orthonormal", name, i, j, m)
+			}
+		}
+	}
+}
+
+func checkRotation(t *testing.T, name string, m *T) {
+	checkOrthonormal(t, name, m)
+	if det := m.Determinant(); fmath.Abs(det-1) > testEpsilon {
+		t.Errorf("%s: determinant of %v is %f, want 1", name, m, det)
+	}
+}
+
+func checkEigen(t *testing.T, name string, m *T) vec3.T {
+	values, vectors := m.SymmetricEigen()
+	checkRotation(t, name, &vectors)
+	if values[0] < values[1] || values[1] < values[2] {
+		t.Errorf("%s: eigenvalues %v are not in decreasing order", name, values)
+	}
+	tolerance := testEpsilon * (1 + size(m))
+	for i := 0; i < 3; i++ {
+		mv := m.MulVec3(&vectors[i])
+		lv := vectors[i].Scaled(values[i])
+		if d := distance(&mv, &lv); d > tolerance {
+			t.Errorf("%s: column %d of %v is no eigenvector for %f", name, i, vectors, values[i])
+		}
+	}
+	return values
+}
+
+func checkSVD(t *testing.T, name string, m *T, rotations bool) vec3.T {
+	u, sigma, v := m.SVD(rotations)
+	if rotations {
+		checkRotation(t, name+" U", &u)
+		checkRotation(t, name+" V", &v)
+	} else {
+		if sigma[2] < 0 {
+			t.Errorf("%s: negative singular values %v", name, sigma)
+		}
+		// U contains the reflection of a reflective matrix.
+		checkOrthonormal(t, name+" U", &u)
+		checkRotation(t, name+" V", &v)
+	}
+	if fmath.Abs(sigma[0]) < fmath.Abs(sigma[1]) || fmath.Abs(sigma[1]) < fmath.Abs(sigma[2]) {
+		t.Errorf("%s: singular values %v are not in decreasing order", name, sigma)
+	}
+
+	// Rebuild m from U * diag(sigma) * V^T.
+	var r T
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			for k := 0; k < 3; k++ {
+				r[col][row] += u[k][row] * sigma[k] * v[k][col]
+			}
+		}
+	}
+	tolerance := testEpsilon * (1 + size(m))
+	for col := 0; col < 3; col++ {
+		if d := distance(&r[col], &m[col]); d > tolerance {
+			t.Errorf("%s: U*diag(sigma)*V^T = %v, want %v", name, r, m)
+			break
+		}
+	}
+	return sigma
+}
+
+func TestSymmetricEigen(t *testing.T) {
+	for _, test := range eigenTests {
+		values := checkEigen(t, test.name, &test.m)
+		if d := distance(&values, &test.want); d > testEpsilon*(1+size(&test.m)) {
+			t.Errorf("%s: eigenvalues %v, want %v", test.name, values, test.want)
+		}
+	}
+}
+
+func TestSymmetricEigenRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 1000; n++ {
+		var m T
+		for col := 0; col < 3; col++ {
+			for row := 0; row <= col; row++ {
+				m[col][row] = float32(r.Float64()*4 - 2)
+				m[row][col] = m[col][row]
+			}
+		}
+		checkEigen(t, "random", &m)
+	}
+}
+
+func TestSVD(t *testing.T) {
+	for _, test := range svdTests {
+		sigma := checkSVD(t, test.name, &test.m, true)
+		if reflective := sigma[2] < 0; reflective != test.reflective {
+			t.Errorf("%s: singular values %v, want reflective %v", test.name, sigma, test.reflective)
+		}
+		checkSVD(t, test.name, &test.m, false)
+	}
+}
+
+func TestSVDRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 1000; n++ {
+		var m T
+		for col := 0; col < 3; col++ {
+			for row := 0; row < 3; row++ {
+				m[col][row] = float32(r.Float64()*4 - 2)
+			}
+		}
+		checkSVD(t, "random", &m, true)
+		checkSVD(t, "random", &m, false)
+	}
+}
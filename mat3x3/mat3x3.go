@@ -1,6 +1,7 @@
 package mat3x3
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/barnex/fmath"
@@ -10,6 +11,10 @@ import (
 	"github.com/ungerik/go3d/vec3"
 )
 
+// ErrSingular is returned when a singular matrix
+// that has no inverse should be inverted.
+var ErrSingular = errors.New("mat3x3: matrix is singular")
+
 var (
 	Zero  = T{}
 	Ident = T{
@@ -85,6 +90,11 @@ func (self *T) Get(col, row int) float32 {
 	return self[col][row]
 }
 
+// Set sets one element of the matrix.
+func (self *T) Set(col, row int, value float32) {
+	self[col][row] = value
+}
+
 // IsZero checks if all elements of the matrix are zero.
 func (self *T) IsZero() bool {
 	return *self == Zero
@@ -145,23 +155,51 @@ func (self *T) AssignMat2x2(m *mat2x2.T) *T {
 func (self *T) MulVec3(vec *vec3.T) vec3.T {
 	return vec3.T{
 		self[0][0]*vec[0] + self[1][0]*vec[1] + self[2][0]*vec[2],
-		self[0][1]*vec[1] + self[1][1]*vec[1] + self[2][1]*vec[2],
-		self[0][2]*vec[2] + self[1][2]*vec[1] + self[2][2]*vec[2],
+		self[0][1]*vec[0] + self[1][1]*vec[1] + self[2][1]*vec[2],
+		self[0][2]*vec[0] + self[1][2]*vec[1] + self[2][2]*vec[2],
 	}
 }
 
+// Quaternion returns the rotation of the matrix as quaternion.
+// The matrix must be a rotation matrix.
 func (self *T) Quaternion() quaternion.T {
+	// Divide by the largest of the quaternion components
+	// to stay accurate for rotations close to 180 degrees.
+	var q quaternion.T
 	tr := self.Trace()
-
-	s := fmath.Sqrt(tr + 1)
-	w := s * 0.5
-	s = 0.5 / s
-
-	q := quaternion.T{
-		(self[1][2] - self[2][1]) * s,
-		(self[2][0] - self[0][2]) * s,
-		(self[0][1] - self[1][0]) * s,
-		w,
+	switch {
+	case tr > 0:
+		s := fmath.Sqrt(tr+1) * 2
+		q = quaternion.T{
+			(self[1][2] - self[2][1]) / s,
+			(self[2][0] - self[0][2]) / s,
+			(self[0][1] - self[1][0]) / s,
+			s * 0.25,
+		}
+	case self[0][0] > self[1][1] && self[0][0] > self[2][2]:
+		s := fmath.Sqrt(1+self[0][0]-self[1][1]-self[2][2]) * 2
+		q = quaternion.T{
+			s * 0.25,
+			(self[0][1] + self[1][0]) / s,
+			(self[2][0] + self[0][2]) / s,
+			(self[1][2] - self[2][1]) / s,
+		}
+	case self[1][1] > self[2][2]:
+		s := fmath.Sqrt(1+self[1][1]-self[0][0]-self[2][2]) * 2
+		q = quaternion.T{
+			(self[0][1] + self[1][0]) / s,
+			s * 0.25,
+			(self[1][2] + self[2][1]) / s,
+			(self[2][0] - self[0][2]) / s,
+		}
+	default:
+		s := fmath.Sqrt(1+self[2][2]-self[0][0]-self[1][1]) * 2
+		q = quaternion.T{
+			(self[2][0] + self[0][2]) / s,
+			(self[1][2] + self[2][1]) / s,
+			s * 0.25,
+			(self[0][1] - self[1][0]) / s,
+		}
 	}
 	return q.Normalized()
 }
@@ -326,3 +364,69 @@ func (self *T) Transpose() *T {
 	swap(&self[2][1], &self[1][2])
 	return self
 }
+
+// Adjugate replaces the matrix with its adjugate,
+// the transpose of its cofactor matrix, and returns self.
+func (self *T) Adjugate() *T {
+	*self = self.Adjugated()
+	return self
+}
+
+// Adjugated returns the adjugate of the matrix,
+// the transpose of its cofactor matrix.
+func (self *T) Adjugated() T {
+	m := self
+	return T{
+		vec3.T{
+			m[1][1]*m[2][2] - m[2][1]*m[1][2],
+			m[2][1]*m[0][2] - m[0][1]*m[2][2],
+			m[0][1]*m[1][2] - m[1][1]*m[0][2],
+		},
+		vec3.T{
+			m[2][0]*m[1][2] - m[1][0]*m[2][2],
+			m[0][0]*m[2][2] - m[2][0]*m[0][2],
+			m[1][0]*m[0][2] - m[0][0]*m[1][2],
+		},
+		vec3.T{
+			m[1][0]*m[2][1] - m[2][0]*m[1][1],
+			m[2][0]*m[0][1] - m[0][0]*m[2][1],
+			m[0][0]*m[1][1] - m[1][0]*m[0][1],
+		},
+	}
+}
+
+// Invert inverts the matrix.
+// If the matrix is singular ErrSingular is returned and the matrix is not modified.
+func (self *T) Invert() error {
+	det := self.Determinant()
+	if det == 0 {
+		return ErrSingular
+	}
+	self.Adjugate()
+	ooDet := 1 / det
+	self[0].Scale(ooDet)
+	self[1].Scale(ooDet)
+	self[2].Scale(ooDet)
+	return nil
+}
+
+// Inverted returns an inverted copy of the matrix.
+// If the matrix is singular ErrSingular is returned.
+func (self *T) Inverted() (T, error) {
+	r := *self
+	err := r.Invert()
+	return r, err
+}
+
+// Solve returns x for the linear equation system self * x = b.
+// If the matrix is singular ErrSingular is returned.
+func (self *T) Solve(b *vec3.T) (vec3.T, error) {
+	det := self.Determinant()
+	if det == 0 {
+		return vec3.Zero, ErrSingular
+	}
+	adj := self.Adjugated()
+	x := adj.MulVec3(b)
+	x.Scale(1 / det)
+	return x, nil
+}
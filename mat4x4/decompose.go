@@ -0,0 +1,67 @@
+package mat4x4
+
+import (
+	"github.com/ungerik/go3d/mat3x3"
+	"github.com/ungerik/go3d/quaternion"
+	"github.com/ungerik/go3d/vec3"
+)
+
+// Decompose splits the matrix into a translation, a rotation and a scale
+// so that Compose(&translation, &rotation, &scale) returns the matrix.
+// The X scale of a reflective matrix is negative.
+// Shear can't be represented and is lost, see PolarDecompose.
+// The matrix must be affine.
+func (self *T) Decompose() (translation vec3.T, rotation quaternion.T, scale vec3.T) {
+	translation = vec3.T{self[3][0], self[3][1], self[3][2]}
+
+	m := self.upper3x3()
+	reflective := m.IsReflective()
+	if reflective {
+		m[0].Invert()
+	}
+	r, stretch := polarDecompose(&m)
+	scale = vec3.T{stretch[0][0], stretch[1][1], stretch[2][2]}
+	if reflective {
+		scale[0] = -scale[0]
+	}
+	return translation, r.Quaternion(), scale
+}
+
+// PolarDecompose splits the upper 3x3 matrix into rotation * stretch,
+// where rotation is a rotation matrix and stretch is a symmetric matrix
+// that contains the scale and the shear.
+// If the matrix is reflective, stretch has a negative eigenvalue.
+func (self *T) PolarDecompose() (rotation, stretch mat3x3.T) {
+	m := self.upper3x3()
+	return polarDecompose(&m)
+}
+
+func polarDecompose(m *mat3x3.T) (rotation, stretch mat3x3.T) {
+	// With m = U * diag(sigma) * V^T follows
+	// rotation = U * V^T and stretch = V * diag(sigma) * V^T.
+	u, sigma, v := m.SVD(true)
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			for k := 0; k < 3; k++ {
+				rotation[col][row] += u[k][row] * v[k][col]
+				stretch[col][row] += v[k][row] * sigma[k] * v[k][col]
+			}
+		}
+	}
+	return rotation, stretch
+}
+
+// Compose sets the matrix to translate * rotate * scale and returns self.
+// It is the inverse of Decompose.
+func (self *T) Compose(translation *vec3.T, rotation *quaternion.T, scale *vec3.T) *T {
+	self.AssignQuaternion(rotation)
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			self[col][row] *= scale[col]
+		}
+	}
+	self[3][0] = translation[0]
+	self[3][1] = translation[1]
+	self[3][2] = translation[2]
+	return self
+}
@@ -0,0 +1,191 @@
+package mat4x4
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/barnex/fmath"
+	"github.com/ungerik/go3d/mat3x3"
+	"github.com/ungerik/go3d/quaternion"
+	"github.com/ungerik/go3d/vec3"
+)
+
+// testEpsilon is the tolerance of the decomposition tests.
+const testEpsilon = 1e-4 //gend:float64 1e-10
+
+func random(r *rand.Rand, min, max float32) float32 {
+	return min + float32(r.Float64())*(max-min)
+}
+
+func randomQuaternion(r *rand.Rand) quaternion.T {
+	axis := vec3.T{random(r, -1, 1), random(r, -1, 1), random(r, -1, 1)}
+	for axis.LengthSqr() == 0 {
+		axis = vec3.T{random(r, -1, 1), random(r, -1, 1), random(r, -1, 1)}
+	}
+	axis.Normalize()
+	return quaternion.FromAxisAngle(&axis, random(r, -math.Pi, math.Pi))
+}
+
+func equal(a, b *T, tolerance float32) bool {
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			if fmath.Abs(a[col][row]-b[col][row]) > tolerance {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sameRotation returns true if a and b are equal or negated.
+func sameRotation(a, b *quaternion.T) bool {
+	return fmath.Abs(quaternion.Dot(a, b)) >= 1-testEpsilon
+}
+
+func TestComposeDecompose(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 5000; n++ {
+		translation := vec3.T{random(r, -10, 10), random(r, -10, 10), random(r, -10, 10)}
+		rotation := randomQuaternion(r)
+		scale := vec3.T{random(r, 0.1, 3), random(r, 0.1, 3), random(r, 0.1, 3)}
+		positive := true
+		switch n % 4 {
+		case 1:
+			// Negative scales, including reflections.
+			for i := 0; i < 3; i++ {
+				if r.Intn(2) == 0 {
+					scale[i] = -scale[i]
+					positive = false
+				}
+			}
+		case 2:
+			// A zero scale.
+			scale[r.Intn(3)] = 0
+			positive = false
+		}
+
+		var m T
+		m.Compose(&translation, &rotation, &scale)
+		t2, r2, s2 := m.Decompose()
+		if !r2.IsUnitQuat(testEpsilon) {
+			t.Fatalf("Decompose(%v) returned the rotation %v which is no unit quaternion", m, r2)
+		}
+		var m2 T
+		m2.Compose(&t2, &r2, &s2)
+		if !equal(&m, &m2, testEpsilon*10) {
+			t.Fatalf("Compose(Decompose(%v)) = %v", m, m2)
+		}
+		if t2 != translation {
+			t.Fatalf("Decompose(%v) returned the translation %v, want %v", m, t2, translation)
+		}
+		if positive {
+			d := vec3.Sub(&s2, &scale)
+			if d.Length() > testEpsilon*10 || !sameRotation(&r2, &rotation) {
+				t.Fatalf("Decompose(%v) = %v, %v, want %v, %v", m, r2, s2, rotation, scale)
+			}
+		}
+	}
+}
+
+func TestDecomposeReflection(t *testing.T) {
+	rotation := quaternion.FromYAxisAngle(0.5)
+	for _, scale := range []vec3.T{{-2, 3, 4}, {2, -3, 4}, {2, 3, -4}, {-2, -3, -4}} {
+		var m T
+		m.Compose(&vec3.Zero, &rotation, &scale)
+		_, r2, s2 := m.Decompose()
+		if s2[0] >= 0 || s2[1] <= 0 || s2[2] <= 0 {
+			t.Errorf("Decompose of scale %v returned scale %v, want only a negative X scale", scale, s2)
+		}
+		var m2 T
+		m2.Compose(&vec3.Zero, &r2, &s2)
+		if !equal(&m, &m2, testEpsilon*10) {
+			t.Errorf("Compose(Decompose(%v)) = %v", m, m2)
+		}
+	}
+}
+
+func TestQuaternion180(t *testing.T) {
+	axes := []vec3.T{
+		{1, 0, 0}, {0, 1, 0}, {0, 0, 1},
+		{1, 1, 0}, {0, 1, 1}, {1, 0, 1}, {1, -2, 3},
+	}
+	for _, axis := range axes {
+		axis.Normalize()
+		for _, angle := range []float32{math.Pi, math.Pi * 0.999, -math.Pi * 0.999} {
+			q := quaternion.FromAxisAngle(&axis, angle)
+
+			var m3 mat3x3.T
+			m3.AssignQuaternion(&q)
+			if q3 := m3.Quaternion(); !sameRotation(&q3, &q) {
+				t.Errorf("mat3x3.Quaternion of %v = %v", q, q3)
+			}
+
+			var m T
+			m.AssignQuaternion(&q)
+			if q4 := m.Quaternion(); !sameRotation(&q4, &q) {
+				t.Errorf("Quaternion of %v = %v", q, q4)
+			}
+
+			scale := vec3.T{1, 2, 3}
+			m.Compose(&vec3.UnitX, &q, &scale)
+			if _, q4, _ := m.Decompose(); !sameRotation(&q4, &q) {
+				t.Errorf("Decompose rotation of %v = %v", q, q4)
+			}
+		}
+	}
+}
+
+// TestQuaternionTrace is a regression test for Quaternion
+// using the trace of the full 4x4 matrix.
+func TestQuaternionTrace(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for n := 0; n < 1000; n++ {
+		q := randomQuaternion(r)
+		var m T
+		m.AssignQuaternion(&q)
+		if q2 := m.Quaternion(); !sameRotation(&q2, &q) {
+			t.Fatalf("Quaternion of %v = %v", q, q2)
+		}
+	}
+
+	var m T
+	m.AssignZRotation(math.Pi / 2)
+	want := quaternion.FromZAxisAngle(math.Pi / 2)
+	if q := m.Quaternion(); !sameRotation(&q, &want) {
+		t.Errorf("Quaternion of a 90 degree Z rotation = %v, want %v", q, want)
+	}
+}
+
+func TestPolarDecompose(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for n := 0; n < 1000; n++ {
+		m := Ident
+		if n == 0 {
+			// Shear X along Y.
+			m[1][0] = 0.5
+		} else {
+			for col := 0; col < 3; col++ {
+				for row := 0; row < 3; row++ {
+					m[col][row] = random(r, -2, 2)
+				}
+			}
+		}
+		rotation, stretch := m.PolarDecompose()
+		if det := rotation.Determinant(); fmath.Abs(det-1) > testEpsilon*10 {
+			t.Fatalf("PolarDecompose(%v) returned the rotation %v with determinant %f", m, rotation, det)
+		}
+		var p mat3x3.T
+		p.AssignMul(&rotation, &stretch)
+		for col := 0; col < 3; col++ {
+			for row := 0; row < 3; row++ {
+				if fmath.Abs(p[col][row]-m[col][row]) > testEpsilon*10 {
+					t.Fatalf("rotation*stretch = %v, want the upper 3x3 of %v", p, m)
+				}
+				if fmath.Abs(stretch[col][row]-stretch[row][col]) > testEpsilon*10 {
+					t.Fatalf("PolarDecompose(%v) returned the asymmetric stretch %v", m, stretch)
+				}
+			}
+		}
+	}
+}
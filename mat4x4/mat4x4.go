@@ -1,6 +1,7 @@
 package mat4x4
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/barnex/fmath"
@@ -12,6 +13,10 @@ import (
 	"github.com/ungerik/go3d/vec4"
 )
 
+// ErrSingular is returned when a singular matrix
+// that has no inverse should be inverted.
+var ErrSingular = errors.New("mat4x4: matrix is singular")
+
 var (
 	Zero  = T{}
 	Ident = T{
@@ -22,6 +27,43 @@ var (
 	}
 )
 
+// ClipSpace selects the depth range and the Y direction
+// of the clip space that projection matrices map to.
+type ClipSpace int
+
+const (
+	// ClipSpaceOpenGL has a depth range of -1..1 and Y pointing up.
+	ClipSpaceOpenGL ClipSpace = iota
+	// ClipSpaceDirectX has a depth range of 0..1 and Y pointing up.
+	ClipSpaceDirectX
+	// ClipSpaceVulkan has a depth range of 0..1 and Y pointing down.
+	ClipSpaceVulkan
+)
+
+// depthRange returns the normalized device depth of the near and the far plane.
+func (self ClipSpace) depthRange() (near, far float32) {
+	if self == ClipSpaceOpenGL {
+		return -1, 1
+	}
+	return 0, 1
+}
+
+// windowDepth maps the normalized device depth z to the window depth range 0..1.
+func (self ClipSpace) windowDepth(z float32) float32 {
+	if self == ClipSpaceOpenGL {
+		return (z + 1) * 0.5
+	}
+	return z
+}
+
+// ndcDepth maps the window depth z in the range 0..1 to the normalized device depth.
+func (self ClipSpace) ndcDepth(z float32) float32 {
+	if self == ClipSpaceOpenGL {
+		return z*2 - 1
+	}
+	return z
+}
+
 type T [4]vec4.T
 
 // From copies a T from a generic.T implementation.
@@ -87,6 +129,11 @@ func (self *T) Get(col, row int) float32 {
 	return self[col][row]
 }
 
+// Set sets one element of the matrix.
+func (self *T) Set(col, row int, value float32) {
+	self[col][row] = value
+}
+
 // IsZero checks if all elements of the matrix are zero.
 func (self *T) IsZero() bool {
 	return *self == Zero
@@ -145,9 +192,9 @@ func (self *T) AssignMul(a, b *T) *T {
 func (self *T) MulVec4(vec *vec4.T) vec4.T {
 	return vec4.T{
 		self[0][0]*vec[0] + self[1][0]*vec[1] + self[2][0]*vec[2] + self[3][0]*vec[3],
-		self[0][1]*vec[1] + self[1][1]*vec[1] + self[2][1]*vec[2] + self[3][1]*vec[3],
-		self[0][2]*vec[2] + self[1][2]*vec[1] + self[2][2]*vec[2] + self[3][2]*vec[3],
-		self[0][3]*vec[3] + self[1][3]*vec[1] + self[2][3]*vec[2] + self[3][3]*vec[3],
+		self[0][1]*vec[0] + self[1][1]*vec[1] + self[2][1]*vec[2] + self[3][1]*vec[3],
+		self[0][2]*vec[0] + self[1][2]*vec[1] + self[2][2]*vec[2] + self[3][2]*vec[3],
+		self[0][3]*vec[0] + self[1][3]*vec[1] + self[2][3]*vec[2] + self[3][3]*vec[3],
 	}
 }
 
@@ -198,13 +245,6 @@ func (self *T) SetScaling(s *vec4.T) *T {
 	return self
 }
 
-func (self *T) Scale(s float32) *T {
-	self[0][0] *= s
-	self[1][1] *= s
-	self[2][2] *= s
-	return self
-}
-
 func (self *T) ScaleVec3(s *vec3.T) *T {
 	self[0][0] *= s[0]
 	self[1][1] *= s[1]
@@ -212,20 +252,20 @@ func (self *T) ScaleVec3(s *vec3.T) *T {
 	return self
 }
 
+// Quaternion returns the rotation of the upper 3x3 matrix as quaternion.
+// The upper 3x3 matrix must be a rotation matrix, see also Decompose.
 func (self *T) Quaternion() quaternion.T {
-	tr := self.Trace()
-
-	s := fmath.Sqrt(tr + 1)
-	w := s * 0.5
-	s = 0.5 / s
+	m := self.upper3x3()
+	return m.Quaternion()
+}
 
-	q := quaternion.T{
-		(self[1][2] - self[2][1]) * s,
-		(self[2][0] - self[0][2]) * s,
-		(self[0][1] - self[1][0]) * s,
-		w,
+// upper3x3 returns the upper 3x3 matrix.
+func (self *T) upper3x3() mat3x3.T {
+	return mat3x3.T{
+		vec3.T{self[0][0], self[0][1], self[0][2]},
+		vec3.T{self[1][0], self[1][1], self[1][2]},
+		vec3.T{self[2][0], self[2][1], self[2][2]},
 	}
-	return q.Normalized()
 }
 
 func (self *T) AssignQuaternion(q *quaternion.T) *T {
@@ -453,6 +493,117 @@ func (self *T) AssignOrthogonalProjection(left, right, bottom, top, znear, zfar
 	return self
 }
 
+// AssignLookAt assigns a right handed view matrix for a camera at eye
+// that looks at center with up as approximate up direction.
+// The camera looks down the negative Z axis of the view space.
+func (self *T) AssignLookAt(eye, center, up *vec3.T) *T {
+	f := vec3.Sub(center, eye)
+	f.Normalize()
+	s := vec3.Cross(&f, up)
+	s.Normalize()
+	u := vec3.Cross(&s, &f)
+
+	self[0][0] = s[0]
+	self[1][0] = s[1]
+	self[2][0] = s[2]
+	self[3][0] = -vec3.Dot(&s, eye)
+
+	self[0][1] = u[0]
+	self[1][1] = u[1]
+	self[2][1] = u[2]
+	self[3][1] = -vec3.Dot(&u, eye)
+
+	self[0][2] = -f[0]
+	self[1][2] = -f[1]
+	self[2][2] = -f[2]
+	self[3][2] = vec3.Dot(&f, eye)
+
+	self[0][3] = 0
+	self[1][3] = 0
+	self[2][3] = 0
+	self[3][3] = 1
+
+	return self
+}
+
+// AssignPerspectiveFov assigns a perspective projection with the vertical
+// field of view fovY in radians and the aspect ratio width / height.
+// The depth range and Y direction of the clip space are selected by clip.
+func (self *T) AssignPerspectiveFov(fovY, aspect, znear, zfar float32, clip ClipSpace) *T {
+	nearDepth, farDepth := clip.depthRange()
+	return self.assignPerspectiveFov(fovY, aspect, znear, zfar, nearDepth, farDepth, clip)
+}
+
+// AssignPerspectiveFovReverseZ works like AssignPerspectiveFov
+// but maps znear to the far end and zfar to the near end of the depth range.
+// Together with a floating point depth buffer and a 0..1 depth range
+// this distributes the depth precision much more evenly.
+func (self *T) AssignPerspectiveFovReverseZ(fovY, aspect, znear, zfar float32, clip ClipSpace) *T {
+	nearDepth, farDepth := clip.depthRange()
+	return self.assignPerspectiveFov(fovY, aspect, znear, zfar, farDepth, nearDepth, clip)
+}
+
+// AssignInfinitePerspectiveFov works like AssignPerspectiveFov
+// but with the far plane at infinity.
+func (self *T) AssignInfinitePerspectiveFov(fovY, aspect, znear float32, clip ClipSpace) *T {
+	nearDepth, farDepth := clip.depthRange()
+	return self.assignInfinitePerspectiveFov(fovY, aspect, znear, nearDepth, farDepth, clip)
+}
+
+// AssignInfinitePerspectiveFovReverseZ works like AssignPerspectiveFovReverseZ
+// but with the far plane at infinity.
+func (self *T) AssignInfinitePerspectiveFovReverseZ(fovY, aspect, znear float32, clip ClipSpace) *T {
+	nearDepth, farDepth := clip.depthRange()
+	return self.assignInfinitePerspectiveFov(fovY, aspect, znear, farDepth, nearDepth, clip)
+}
+
+// assignPerspectiveFov maps the view space depth znear to the
+// normalized device depth nearDepth and zfar to farDepth.
+func (self *T) assignPerspectiveFov(fovY, aspect, znear, zfar, nearDepth, farDepth float32, clip ClipSpace) *T {
+	oo_far_near := 1 / (zfar - znear)
+	self.assignPerspectiveFovDepth(
+		fovY, aspect,
+		(nearDepth-farDepth)*zfar*oo_far_near-nearDepth,
+		(nearDepth-farDepth)*znear*zfar*oo_far_near,
+		clip,
+	)
+	return self
+}
+
+// assignInfinitePerspectiveFov is the limit of assignPerspectiveFov for zfar towards infinity.
+func (self *T) assignInfinitePerspectiveFov(fovY, aspect, znear, nearDepth, farDepth float32, clip ClipSpace) *T {
+	self.assignPerspectiveFovDepth(fovY, aspect, -farDepth, (nearDepth-farDepth)*znear, clip)
+	return self
+}
+
+func (self *T) assignPerspectiveFovDepth(fovY, aspect, depthScale, depthOffset float32, clip ClipSpace) {
+	f := 1 / fmath.Tan(fovY*0.5)
+	if clip == ClipSpaceVulkan {
+		self[1][1] = -f
+	} else {
+		self[1][1] = f
+	}
+
+	self[0][0] = f / aspect
+	self[1][0] = 0
+	self[2][0] = 0
+	self[3][0] = 0
+
+	self[0][1] = 0
+	self[2][1] = 0
+	self[3][1] = 0
+
+	self[0][2] = 0
+	self[1][2] = 0
+	self[2][2] = depthScale
+	self[3][2] = depthOffset
+
+	self[0][3] = 0
+	self[1][3] = 0
+	self[2][3] = -1
+	self[3][3] = 0
+}
+
 func (self *T) ExtractEulerAngles() (yHead, xPitch, zRoll float32) {
 	xPitch = fmath.Asin(self[1][2])
 	f12 := fmath.Abs(self[1][2])
@@ -498,3 +649,269 @@ func (self *T) Transpose3x3() *T {
 	swap(&self[2][1], &self[1][2])
 	return self
 }
+
+// Determinant returns the determinant of the full 4x4 matrix.
+// See also Determinant3x3.
+func (self *T) Determinant() float32 {
+	s, c := self.subDeterminants()
+	return s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+}
+
+// subDeterminants returns the 2x2 sub-determinants of the first two
+// and the last two columns that are shared by Determinant and Invert.
+func (self *T) subDeterminants() (s, c [6]float32) {
+	m := self
+	s[0] = m[0][0]*m[1][1] - m[1][0]*m[0][1]
+	s[1] = m[0][0]*m[1][2] - m[1][0]*m[0][2]
+	s[2] = m[0][0]*m[1][3] - m[1][0]*m[0][3]
+	s[3] = m[0][1]*m[1][2] - m[1][1]*m[0][2]
+	s[4] = m[0][1]*m[1][3] - m[1][1]*m[0][3]
+	s[5] = m[0][2]*m[1][3] - m[1][2]*m[0][3]
+
+	c[0] = m[2][0]*m[3][1] - m[3][0]*m[2][1]
+	c[1] = m[2][0]*m[3][2] - m[3][0]*m[2][2]
+	c[2] = m[2][0]*m[3][3] - m[3][0]*m[2][3]
+	c[3] = m[2][1]*m[3][2] - m[3][1]*m[2][2]
+	c[4] = m[2][1]*m[3][3] - m[3][1]*m[2][3]
+	c[5] = m[2][2]*m[3][3] - m[3][2]*m[2][3]
+	return s, c
+}
+
+// Invert inverts the matrix.
+// If the matrix is singular ErrSingular is returned and the matrix is not modified.
+// See also InvertAffine for a faster version for affine transformations.
+func (self *T) Invert() error {
+	m := self
+	s, c := self.subDeterminants()
+	det := s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+	if det == 0 {
+		return ErrSingular
+	}
+	ooDet := 1 / det
+
+	*self = T{
+		vec4.T{
+			(m[1][1]*c[5] - m[1][2]*c[4] + m[1][3]*c[3]) * ooDet,
+			(-m[0][1]*c[5] + m[0][2]*c[4] - m[0][3]*c[3]) * ooDet,
+			(m[3][1]*s[5] - m[3][2]*s[4] + m[3][3]*s[3]) * ooDet,
+			(-m[2][1]*s[5] + m[2][2]*s[4] - m[2][3]*s[3]) * ooDet,
+		},
+		vec4.T{
+			(-m[1][0]*c[5] + m[1][2]*c[2] - m[1][3]*c[1]) * ooDet,
+			(m[0][0]*c[5] - m[0][2]*c[2] + m[0][3]*c[1]) * ooDet,
+			(-m[3][0]*s[5] + m[3][2]*s[2] - m[3][3]*s[1]) * ooDet,
+			(m[2][0]*s[5] - m[2][2]*s[2] + m[2][3]*s[1]) * ooDet,
+		},
+		vec4.T{
+			(m[1][0]*c[4] - m[1][1]*c[2] + m[1][3]*c[0]) * ooDet,
+			(-m[0][0]*c[4] + m[0][1]*c[2] - m[0][3]*c[0]) * ooDet,
+			(m[3][0]*s[4] - m[3][1]*s[2] + m[3][3]*s[0]) * ooDet,
+			(-m[2][0]*s[4] + m[2][1]*s[2] - m[2][3]*s[0]) * ooDet,
+		},
+		vec4.T{
+			(-m[1][0]*c[3] + m[1][1]*c[1] - m[1][2]*c[0]) * ooDet,
+			(m[0][0]*c[3] - m[0][1]*c[1] + m[0][2]*c[0]) * ooDet,
+			(-m[3][0]*s[3] + m[3][1]*s[1] - m[3][2]*s[0]) * ooDet,
+			(m[2][0]*s[3] - m[2][1]*s[1] + m[2][2]*s[0]) * ooDet,
+		},
+	}
+	return nil
+}
+
+// Inverted returns an inverted copy of the matrix.
+// If the matrix is singular ErrSingular is returned.
+func (self *T) Inverted() (T, error) {
+	r := *self
+	err := r.Invert()
+	return r, err
+}
+
+// InvertAffine inverts a matrix that consists only of rotation, scaling,
+// shearing and translation, which means that the last row is (0, 0, 0, 1).
+// It is cheaper than Invert because only the upper 3x3 matrix
+// has to be inverted.
+// If the matrix is singular ErrSingular is returned and the matrix is not modified.
+func (self *T) InvertAffine() error {
+	inv, err := self.inverse3x3()
+	if err != nil {
+		return err
+	}
+	t := vec3.T{self[3][0], self[3][1], self[3][2]}
+	for col := 0; col < 3; col++ {
+		self[col] = vec4.T{inv[col][0], inv[col][1], inv[col][2], 0}
+	}
+	self[3] = vec4.T{
+		-(inv[0][0]*t[0] + inv[1][0]*t[1] + inv[2][0]*t[2]),
+		-(inv[0][1]*t[0] + inv[1][1]*t[1] + inv[2][1]*t[2]),
+		-(inv[0][2]*t[0] + inv[1][2]*t[1] + inv[2][2]*t[2]),
+		1,
+	}
+	return nil
+}
+
+// InvertedAffine returns a copy of the matrix inverted with InvertAffine.
+func (self *T) InvertedAffine() (T, error) {
+	r := *self
+	err := r.InvertAffine()
+	return r, err
+}
+
+// NormalMatrix returns the inverse transpose of the upper 3x3 matrix
+// that is used to transform normals.
+// If the upper 3x3 matrix is singular ErrSingular is returned.
+func (self *T) NormalMatrix() (mat3x3.T, error) {
+	inv, err := self.inverse3x3()
+	if err != nil {
+		return mat3x3.Zero, err
+	}
+	inv.Transpose()
+	return inv, nil
+}
+
+// inverse3x3 returns the inverse of the upper 3x3 matrix.
+func (self *T) inverse3x3() (mat3x3.T, error) {
+	m := self
+	det := self.Determinant3x3()
+	if det == 0 {
+		return mat3x3.Zero, ErrSingular
+	}
+	ooDet := 1 / det
+	return mat3x3.T{
+		vec3.T{
+			(m[1][1]*m[2][2] - m[2][1]*m[1][2]) * ooDet,
+			(m[2][1]*m[0][2] - m[0][1]*m[2][2]) * ooDet,
+			(m[0][1]*m[1][2] - m[1][1]*m[0][2]) * ooDet,
+		},
+		vec3.T{
+			(m[2][0]*m[1][2] - m[1][0]*m[2][2]) * ooDet,
+			(m[0][0]*m[2][2] - m[2][0]*m[0][2]) * ooDet,
+			(m[1][0]*m[0][2] - m[0][0]*m[1][2]) * ooDet,
+		},
+		vec3.T{
+			(m[1][0]*m[2][1] - m[2][0]*m[1][1]) * ooDet,
+			(m[2][0]*m[0][1] - m[0][0]*m[2][1]) * ooDet,
+			(m[0][0]*m[1][1] - m[1][0]*m[0][1]) * ooDet,
+		},
+	}, nil
+}
+
+// Project transforms the point obj with the model-view-projection matrix mvp
+// into window coordinates. The viewport is given as (x, y, width, height).
+// The returned Z is the window depth in the range 0..1.
+// See also Unproject.
+func Project(obj *vec3.T, mvp *T, viewport *vec4.T, clip ClipSpace) vec3.T {
+	v := vec4.FromVec3(obj)
+	v = mvp.MulVec4(&v)
+	ndc := v.Vec3DividedByW()
+	return vec3.T{
+		viewport[0] + (ndc[0]+1)*0.5*viewport[2],
+		viewport[1] + (ndc[1]+1)*0.5*viewport[3],
+		clip.windowDepth(ndc[2]),
+	}
+}
+
+// Unproject transforms the window coordinates win back to the space
+// that the model-view-projection matrix mvp transforms from.
+// The viewport is given as (x, y, width, height)
+// and the Z of win is the window depth in the range 0..1.
+// If mvp is singular ErrSingular is returned.
+// See also Project.
+func Unproject(win *vec3.T, mvp *T, viewport *vec4.T, clip ClipSpace) (vec3.T, error) {
+	inv, err := mvp.Inverted()
+	if err != nil {
+		return vec3.Zero, err
+	}
+	v := inv.unprojectHomogeneous(win, viewport, clip)
+	return v.Vec3DividedByW(), nil
+}
+
+// ScreenRay returns the ray through the window coordinates x, y
+// that can be used for picking. The origin is on the near plane
+// and dir is normalized. Reverse-Z and infinite projections are supported.
+// The viewport is given as (x, y, width, height).
+// If mvp is singular ErrSingular is returned.
+func ScreenRay(x, y float32, mvp *T, viewport *vec4.T, clip ClipSpace) (origin, dir vec3.T, err error) {
+	inv, err := mvp.Inverted()
+	if err != nil {
+		return vec3.Zero, vec3.Zero, err
+	}
+	near := inv.unprojectHomogeneous(&vec3.T{x, y, 0}, viewport, clip)
+	far := inv.unprojectHomogeneous(&vec3.T{x, y, 1}, viewport, clip)
+	// The W of an unprojected point falls with its distance to the camera,
+	// so swap the points for reverse-Z projections.
+	if fmath.Abs(far[3]) > fmath.Abs(near[3]) {
+		near, far = far, near
+	}
+	// far can be at infinity with W == 0, so calculate the direction
+	// scaled by near[3] * far[3] without dividing by far[3].
+	dir = vec3.T{
+		far[0]*near[3] - near[0]*far[3],
+		far[1]*near[3] - near[1]*far[3],
+		far[2]*near[3] - near[2]*far[3],
+	}
+	dir.Normalize()
+	return near.Vec3DividedByW(), dir, nil
+}
+
+// unprojectHomogeneous transforms win from window coordinates
+// with self as inverse model-view-projection matrix,
+// but without dividing by W.
+func (self *T) unprojectHomogeneous(win *vec3.T, viewport *vec4.T, clip ClipSpace) vec4.T {
+	ndc := vec4.T{
+		(win[0]-viewport[0])/viewport[2]*2 - 1,
+		(win[1]-viewport[1])/viewport[3]*2 - 1,
+		clip.ndcDepth(win[2]),
+		1,
+	}
+	return self.MulVec4(&ndc)
+}
+
+// AssignReflection assigns a matrix that mirrors points at plane.
+func (self *T) AssignReflection(plane *vec3.Plane) *T {
+	p := plane.Normalized()
+	n := &p.Normal
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			self[col][row] = -2 * n[row] * n[col]
+		}
+		self[col][col] += 1
+		self[col][3] = 0
+	}
+	self[3][0] = 2 * p.Distance * n[0]
+	self[3][1] = 2 * p.Distance * n[1]
+	self[3][2] = 2 * p.Distance * n[2]
+	self[3][3] = 1
+	return self
+}
+
+// TransformPlane returns plane transformed by the matrix.
+// Planes have to be transformed by the inverse transpose of the matrix
+// that transforms points, so ErrSingular is returned if the matrix is singular.
+// The returned plane is normalized.
+func (self *T) TransformPlane(plane *vec3.Plane) (vec3.Plane, error) {
+	inv, err := self.Inverted()
+	if err != nil {
+		return vec3.Plane{}, err
+	}
+	p := vec4.T{plane.Normal[0], plane.Normal[1], plane.Normal[2], -plane.Distance}
+	r := vec3.Plane{
+		Normal:   vec3.T{vec4.Dot4(&inv[0], &p), vec4.Dot4(&inv[1], &p), vec4.Dot4(&inv[2], &p)},
+		Distance: -vec4.Dot4(&inv[3], &p),
+	}
+	return *r.Normalize(), nil
+}
+
+// TransformSphere returns a sphere that contains sphere transformed by the matrix.
+// For non uniform scaling the radius is scaled by the largest scale factor,
+// so the result is not minimal in that case.
+func (self *T) TransformSphere(sphere *vec3.Sphere) vec3.Sphere {
+	center := self.MulVec3(&sphere.Center)
+	var scaleSqr float32
+	for col := 0; col < 3; col++ {
+		axis := vec3.T{self[col][0], self[col][1], self[col][2]}
+		if l := axis.LengthSqr(); l > scaleSqr {
+			scaleSqr = l
+		}
+	}
+	return vec3.Sphere{Center: center, Radius: sphere.Radius * fmath.Sqrt(scaleSqr)}
+}
@@ -0,0 +1,72 @@
+package matn
+
+import (
+	"github.com/barnex/fmath"
+)
+
+// Cholesky is the decomposition A = L*L^T of a symmetric positive definite matrix.
+type Cholesky struct {
+	l *T
+}
+
+// Cholesky returns the Cholesky decomposition of the symmetric positive definite matrix.
+// Only the lower triangle of the matrix is read.
+// If the matrix is not positive definite ErrNotPositiveDefinite is returned.
+func (self *T) Cholesky() (*Cholesky, error) {
+	self.mustBeSquare()
+	n := self.rows
+	l := New(n, n)
+	a, d := self.data, l.data
+	for j := 0; j < n; j++ {
+		diag := a[j*n+j]
+		for k := 0; k < j; k++ {
+			diag -= d[k*n+j] * d[k*n+j]
+		}
+		if diag <= 0 {
+			return nil, ErrNotPositiveDefinite
+		}
+		ljj := fmath.Sqrt(diag)
+		d[j*n+j] = ljj
+		for i := j + 1; i < n; i++ {
+			s := a[j*n+i]
+			for k := 0; k < j; k++ {
+				s -= d[k*n+i] * d[k*n+j]
+			}
+			d[j*n+i] = s / ljj
+		}
+	}
+	return &Cholesky{l: l}, nil
+}
+
+// L returns the lower triangular factor.
+func (self *Cholesky) L() *T {
+	return self.l.Copy()
+}
+
+// Solve returns X solving A*X = b for every column of b.
+func (self *Cholesky) Solve(b *T) *T {
+	n := self.l.rows
+	if b.rows != n {
+		panic("matn: right hand side has wrong number of rows")
+	}
+	x := b.Copy()
+	d := self.l.data
+	for col := 0; col < x.cols; col++ {
+		v := x.Col(col)
+		// Solve L * y = b
+		for k := 0; k < n; k++ {
+			v[k] /= d[k*n+k]
+			for i := k + 1; i < n; i++ {
+				v[i] -= v[k] * d[k*n+i]
+			}
+		}
+		// Solve L^T * x = y
+		for k := n - 1; k >= 0; k-- {
+			for i := k + 1; i < n; i++ {
+				v[k] -= d[k*n+i] * v[i]
+			}
+			v[k] /= d[k*n+k]
+		}
+	}
+	return x
+}
@@ -0,0 +1,53 @@
+package matn
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCholesky(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for n := 1; n <= 8; n++ {
+		for i := 0; i < 20; i++ {
+			// M^T*M is positive semidefinite, the added identity makes it definite.
+			m := randomMatrix(r, n, n)
+			a := Mul(m.Transposed(), m).Add(NewIdent(n))
+			b := randomMatrix(r, n, 2)
+			cholesky, err := a.Cholesky()
+			if err != nil {
+				t.Fatalf("Cholesky(%v) returned %v", a, err)
+			}
+			l := cholesky.L()
+			for col := 1; col < n; col++ {
+				for row := 0; row < col; row++ {
+					if l.Get(col, row) != 0 {
+						t.Fatalf("L of %v is not lower triangular: %v", a, l)
+					}
+				}
+			}
+			if d := maxAbsDiff(Mul(l, l.Transposed()), a); d > testEpsilon {
+				t.Fatalf("L*L^T-A of %v is %f", a, d)
+			}
+			x := cholesky.Solve(b)
+			if d := maxAbsDiff(Mul(a, x), b); d > testEpsilon {
+				t.Fatalf("A*x-b of Cholesky(%v).Solve(%v) is %f", a, b, d)
+			}
+		}
+	}
+}
+
+func TestCholeskyNotPositiveDefinite(t *testing.T) {
+	tests := []*T{
+		New(2, 2),
+		fromRows([]float32{-1}),
+		// Indefinite with the eigenvalues 3 and -1.
+		fromRows([]float32{1, 2}, []float32{2, 1}),
+		// Positive semidefinite with a zero eigenvalue.
+		fromRows([]float32{1, 1}, []float32{1, 1}),
+	}
+	for _, a := range tests {
+		if _, err := a.Cholesky(); err != ErrNotPositiveDefinite {
+			t.Errorf("Cholesky(%v) returned %v, want ErrNotPositiveDefinite", a, err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package matn
+
+import (
+	"github.com/barnex/fmath"
+)
+
+// LU is the LU decomposition with partial pivoting P*A = L*U of a square matrix.
+type LU struct {
+	// lu holds L below the diagonal without its unit diagonal and U above.
+	lu    *T
+	pivot []int
+	sign  float32
+}
+
+// LU returns the LU decomposition of the square matrix.
+// If the matrix is singular ErrSingular is returned.
+func (self *T) LU() (*LU, error) {
+	self.mustBeSquare()
+	n := self.rows
+	a := self.Copy()
+	d := a.data
+	result := &LU{lu: a, pivot: make([]int, n), sign: 1}
+
+	for k := 0; k < n; k++ {
+		// Find the pivot in column k.
+		p := k
+		for i := k + 1; i < n; i++ {
+			if fmath.Abs(d[k*n+i]) > fmath.Abs(d[k*n+p]) {
+				p = i
+			}
+		}
+		result.pivot[k] = p
+		if d[k*n+p] == 0 {
+			return nil, ErrSingular
+		}
+		if p != k {
+			for j := 0; j < n; j++ {
+				d[j*n+p], d[j*n+k] = d[j*n+k], d[j*n+p]
+			}
+			result.sign = -result.sign
+		}
+
+		ooPivot := 1 / d[k*n+k]
+		for i := k + 1; i < n; i++ {
+			d[k*n+i] *= ooPivot
+		}
+		for j := k + 1; j < n; j++ {
+			f := d[j*n+k]
+			if f == 0 {
+				continue
+			}
+			for i := k + 1; i < n; i++ {
+				d[j*n+i] -= d[k*n+i] * f
+			}
+		}
+	}
+	return result, nil
+}
+
+// L returns the unit lower triangular factor.
+func (self *LU) L() *T {
+	n := self.lu.rows
+	l := NewIdent(n)
+	for col := 0; col < n; col++ {
+		for row := col + 1; row < n; row++ {
+			l.data[col*n+row] = self.lu.data[col*n+row]
+		}
+	}
+	return l
+}
+
+// U returns the upper triangular factor.
+func (self *LU) U() *T {
+	n := self.lu.rows
+	u := New(n, n)
+	for col := 0; col < n; col++ {
+		for row := 0; row <= col; row++ {
+			u.data[col*n+row] = self.lu.data[col*n+row]
+		}
+	}
+	return u
+}
+
+// Determinant returns the determinant of the decomposed matrix.
+func (self *LU) Determinant() float32 {
+	n := self.lu.rows
+	det := self.sign
+	for i := 0; i < n; i++ {
+		det *= self.lu.data[i*n+i]
+	}
+	return det
+}
+
+// Solve returns X solving A*X = b for every column of b.
+func (self *LU) Solve(b *T) *T {
+	n := self.lu.rows
+	if b.rows != n {
+		panic("matn: right hand side has wrong number of rows")
+	}
+	x := b.Copy()
+	d := self.lu.data
+	for col := 0; col < x.cols; col++ {
+		v := x.Col(col)
+		for k, p := range self.pivot {
+			v[k], v[p] = v[p], v[k]
+		}
+		// Forward substitution with the unit lower triangle
+		for k := 0; k < n; k++ {
+			for i := k + 1; i < n; i++ {
+				v[i] -= v[k] * d[k*n+i]
+			}
+		}
+		// Back substitution with the upper triangle
+		for k := n - 1; k >= 0; k-- {
+			v[k] /= d[k*n+k]
+			for i := 0; i < k; i++ {
+				v[i] -= v[k] * d[k*n+i]
+			}
+		}
+	}
+	return x
+}
+
+// Inverse returns the inverse of the decomposed matrix.
+func (self *LU) Inverse() *T {
+	return self.Solve(NewIdent(self.lu.rows))
+}
+
+// Determinant returns the determinant of the square matrix.
+func (self *T) Determinant() float32 {
+	lu, err := self.LU()
+	if err != nil {
+		return 0
+	}
+	return lu.Determinant()
+}
+
+// Inverted returns the inverse of the square matrix.
+// If the matrix is singular ErrSingular is returned.
+func (self *T) Inverted() (*T, error) {
+	lu, err := self.LU()
+	if err != nil {
+		return nil, err
+	}
+	return lu.Inverse(), nil
+}
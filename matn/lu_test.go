@@ -0,0 +1,83 @@
+package matn
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/barnex/fmath"
+)
+
+func TestLU(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 1; n <= 8; n++ {
+		for i := 0; i < 20; i++ {
+			a := randomMatrix(r, n, n)
+			// Strengthen the diagonal to keep the matrix well conditioned.
+			for k := 0; k < n; k++ {
+				a.Set(k, k, a.Get(k, k)+float32(n))
+			}
+			b := randomMatrix(r, n, 2)
+			lu, err := a.LU()
+			if err != nil {
+				t.Fatalf("LU(%v) returned %v", a, err)
+			}
+			x := lu.Solve(b)
+			if d := maxAbsDiff(Mul(a, x), b); d > testEpsilon {
+				t.Fatalf("A*x-b of LU(%v).Solve(%v) is %f", a, b, d)
+			}
+			inverse, err := a.Inverted()
+			if err != nil || maxAbsDiff(Mul(a, inverse), NewIdent(n)) > testEpsilon {
+				t.Fatalf("A*Inverted() of %v is not the identity", a)
+			}
+			// The determinant of L is 1.
+			u := lu.U()
+			det := float32(1)
+			for k := 0; k < n; k++ {
+				det *= u.Get(k, k)
+			}
+			if d := lu.Determinant(); fmath.Abs(fmath.Abs(d)-fmath.Abs(det)) > testEpsilon*fmath.Abs(det) {
+				t.Fatalf("Determinant of %v is %f, want ±%f", a, d, det)
+			}
+		}
+	}
+}
+
+func TestLUPivoting(t *testing.T) {
+	// The zero diagonal element needs row swaps.
+	a := fromRows(
+		[]float32{0, 2, 1},
+		[]float32{1, 0, 0},
+		[]float32{0, 1, 1},
+	)
+	lu, err := a.LU()
+	if err != nil {
+		t.Fatalf("LU(%v) returned %v", a, err)
+	}
+	if d := lu.Determinant(); d != -1 {
+		t.Errorf("Determinant of %v = %f, want -1", a, d)
+	}
+	b := NewVector(3, 1, 2)
+	if x := lu.Solve(b); maxAbsDiff(x, NewVector(1, 1, 1)) > testEpsilon {
+		t.Errorf("Solve(%v) = %v, want 1 1 1", b, x)
+	}
+}
+
+func TestLUSingular(t *testing.T) {
+	tests := []*T{
+		New(3, 3),
+		fromRows([]float32{1, 2}, []float32{2, 4}),
+		// The second row is twice the first one, the elimination stays exact.
+		fromRows([]float32{1, 2, 3}, []float32{2, 4, 6}, []float32{4, 1, 2}),
+	}
+	for _, a := range tests {
+		if _, err := a.LU(); err != ErrSingular {
+			t.Errorf("LU(%v) returned %v, want ErrSingular", a, err)
+		}
+		if _, err := a.Inverted(); err != ErrSingular {
+			t.Errorf("Inverted(%v) returned %v, want ErrSingular", a, err)
+		}
+		if d := a.Determinant(); d != 0 {
+			t.Errorf("Determinant(%v) = %f, want 0", a, d)
+		}
+	}
+}
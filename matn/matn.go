@@ -0,0 +1,254 @@
+// The package matn contains a float32 dense matrix type T of arbitrary size
+// with LU, QR and Cholesky decompositions and least squares solving.
+//
+// Like the fixed size matrices of go3d the elements are stored column by column.
+// Vectors are matrices with a single column.
+package matn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ungerik/go3d/generic"
+)
+
+var (
+	// ErrSingular is returned when solving with a singular
+	// or rank deficient matrix.
+	ErrSingular = errors.New("matn: matrix is singular")
+
+	// ErrNotPositiveDefinite is returned by Cholesky for matrices
+	// that are not symmetric positive definite.
+	ErrNotPositiveDefinite = errors.New("matn: matrix is not positive definite")
+)
+
+// T is a dense matrix.
+type T struct {
+	rows int
+	cols int
+	data []float32
+}
+
+// New returns a zero matrix with rows and cols.
+func New(rows, cols int) *T {
+	return &T{rows: rows, cols: cols, data: make([]float32, rows*cols)}
+}
+
+// NewFromSlice returns a matrix using data with the elements
+// stored column by column as backing memory.
+func NewFromSlice(rows, cols int, data []float32) *T {
+	if len(data) != rows*cols {
+		panic(fmt.Sprintf("matn: %d elements don't fit a %dx%d matrix", len(data), rows, cols))
+	}
+	return &T{rows: rows, cols: cols, data: data}
+}
+
+// NewIdent returns an identity matrix with n rows and columns.
+func NewIdent(n int) *T {
+	self := New(n, n)
+	for i := 0; i < n; i++ {
+		self.data[i*n+i] = 1
+	}
+	return self
+}
+
+// NewVector returns a matrix with a single column of values.
+func NewVector(values ...float32) *T {
+	return NewFromSlice(len(values), 1, append([]float32(nil), values...))
+}
+
+// From copies a T from a generic.T implementation.
+func From(other generic.T) *T {
+	self := New(other.Rows(), other.Cols())
+	for col := 0; col < self.cols; col++ {
+		for row := 0; row < self.rows; row++ {
+			self.data[col*self.rows+row] = other.Get(col, row)
+		}
+	}
+	return self
+}
+
+// Parse parses T from a string. See also String()
+func Parse(s string) (*T, error) {
+	r := strings.NewReader(s)
+	var rows, cols int
+	if _, err := fmt.Fscan(r, &rows, &cols); err != nil {
+		return nil, err
+	}
+	if rows < 0 || cols < 0 {
+		return nil, fmt.Errorf("matn: invalid size %dx%d", rows, cols)
+	}
+	self := New(rows, cols)
+	for i := range self.data {
+		if _, err := fmt.Fscan(r, &self.data[i]); err != nil {
+			return nil, err
+		}
+	}
+	return self, nil
+}
+
+// String formats T as string starting with the number of rows and columns
+// followed by the elements column by column. See also Parse().
+func (self *T) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %d", self.rows, self.cols)
+	for _, v := range self.data {
+		fmt.Fprintf(&b, " %f", v)
+	}
+	return b.String()
+}
+
+// Rows returns the number of rows of the matrix.
+func (self *T) Rows() int {
+	return self.rows
+}
+
+// Cols returns the number of columns of the matrix.
+func (self *T) Cols() int {
+	return self.cols
+}
+
+// Size returns the number elements of the matrix.
+func (self *T) Size() int {
+	return len(self.data)
+}
+
+// Slice returns a copy of the elements of the matrix column by column.
+func (self *T) Slice() []float32 {
+	return append([]float32(nil), self.data...)
+}
+
+// Data returns the backing memory of the matrix with the elements column by column.
+func (self *T) Data() []float32 {
+	return self.data
+}
+
+// Col returns the elements of column col sharing the memory of the matrix.
+func (self *T) Col(col int) []float32 {
+	return self.data[col*self.rows : (col+1)*self.rows]
+}
+
+// Get returns one element of the matrix.
+func (self *T) Get(col, row int) float32 {
+	return self.data[col*self.rows+row]
+}
+
+// Set sets one element of the matrix.
+func (self *T) Set(col, row int, value float32) {
+	self.data[col*self.rows+row] = value
+}
+
+// IsZero checks if all elements of the matrix are zero.
+func (self *T) IsZero() bool {
+	for _, v := range self.data {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Copy returns a copy of the matrix.
+func (self *T) Copy() *T {
+	return &T{rows: self.rows, cols: self.cols, data: self.Slice()}
+}
+
+// Scale multiplies all elements of the matrix by f and returns self.
+func (self *T) Scale(f float32) *T {
+	for i := range self.data {
+		self.data[i] *= f
+	}
+	return self
+}
+
+// Scaled returns a copy of the matrix with all elements multiplied by f.
+func (self *T) Scaled(f float32) *T {
+	return self.Copy().Scale(f)
+}
+
+// Add adds the elements of m and returns self.
+func (self *T) Add(m *T) *T {
+	self.mustHaveSameShape(m)
+	for i, v := range m.data {
+		self.data[i] += v
+	}
+	return self
+}
+
+// Sub subtracts the elements of m and returns self.
+func (self *T) Sub(m *T) *T {
+	self.mustHaveSameShape(m)
+	for i, v := range m.data {
+		self.data[i] -= v
+	}
+	return self
+}
+
+// Transposed returns the transposed matrix.
+func (self *T) Transposed() *T {
+	r := New(self.cols, self.rows)
+	for col := 0; col < self.cols; col++ {
+		for row := 0; row < self.rows; row++ {
+			r.data[row*r.rows+col] = self.data[col*self.rows+row]
+		}
+	}
+	return r
+}
+
+// Transpose transposes the matrix in place and returns self.
+func (self *T) Transpose() *T {
+	*self = *self.Transposed()
+	return self
+}
+
+// AssignMul sets self to the product of a and b and returns self.
+// self must not be a or b. The matrix is resized if necessary.
+func (self *T) AssignMul(a, b *T) *T {
+	if a.cols != b.rows {
+		panic(fmt.Sprintf("matn: can't multiply %dx%d by %dx%d", a.rows, a.cols, b.rows, b.cols))
+	}
+	self.resize(a.rows, b.cols)
+	for col := 0; col < b.cols; col++ {
+		dst := self.Col(col)
+		for i := range dst {
+			dst[i] = 0
+		}
+		// Accumulate the columns of a to keep the memory access sequential.
+		for k, f := range b.Col(col) {
+			if f == 0 {
+				continue
+			}
+			for row, v := range a.Col(k) {
+				dst[row] += v * f
+			}
+		}
+	}
+	return self
+}
+
+// Mul returns the product of a and b.
+func Mul(a, b *T) *T {
+	return New(0, 0).AssignMul(a, b)
+}
+
+func (self *T) resize(rows, cols int) {
+	self.rows = rows
+	self.cols = cols
+	if cap(self.data) < rows*cols {
+		self.data = make([]float32, rows*cols)
+	}
+	self.data = self.data[:rows*cols]
+}
+
+func (self *T) mustHaveSameShape(m *T) {
+	if self.rows != m.rows || self.cols != m.cols {
+		panic(fmt.Sprintf("matn: shapes %dx%d and %dx%d differ", self.rows, self.cols, m.rows, m.cols))
+	}
+}
+
+func (self *T) mustBeSquare() {
+	if self.rows != self.cols {
+		panic(fmt.Sprintf("matn: %dx%d matrix is not square", self.rows, self.cols))
+	}
+}
@@ -0,0 +1,76 @@
+package matn
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/barnex/fmath"
+	"github.com/ungerik/go3d/mat3x3"
+	"github.com/ungerik/go3d/vec4"
+)
+
+// testEpsilon is the tolerance of residuals of well conditioned systems.
+const testEpsilon = 1e-3 //gend:float64 1e-9
+
+// fromRows returns a matrix with the elements given row by row.
+func fromRows(rows ...[]float32) *T {
+	self := New(len(rows), len(rows[0]))
+	for row := range rows {
+		for col, value := range rows[row] {
+			self.Set(col, row, value)
+		}
+	}
+	return self
+}
+
+func randomMatrix(r *rand.Rand, rows, cols int) *T {
+	self := New(rows, cols)
+	for i := range self.data {
+		self.data[i] = float32(r.Float64()*2 - 1)
+	}
+	return self
+}
+
+// maxAbsDiff returns the largest absolute difference of the elements of a and b.
+func maxAbsDiff(a, b *T) float32 {
+	a.mustHaveSameShape(b)
+	var max float32
+	for i := range a.data {
+		if d := fmath.Abs(a.data[i] - b.data[i]); d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+func TestFrom(t *testing.T) {
+	m3 := mat3x3.T{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}
+	m := From(&m3)
+	if m.Rows() != 3 || m.Cols() != 3 {
+		t.Fatalf("From(%v) has %dx%d elements, want 3x3", m3, m.Rows(), m.Cols())
+	}
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			if m.Get(col, row) != m3[col][row] {
+				t.Errorf("From(%v).Get(%d, %d) = %f, want %f", m3, col, row, m.Get(col, row), m3[col][row])
+			}
+		}
+	}
+	// The product of the lifted matrices equals the fixed size product.
+	var square mat3x3.T
+	square.AssignMul(&m3, &m3)
+	if p := Mul(m, m); maxAbsDiff(p, From(&square)) != 0 {
+		t.Errorf("Mul(From(m), From(m)) = %v, want %v", p, square)
+	}
+
+	v := vec4.T{1, -2, 3, -4}
+	m = From(&v)
+	if m.Rows() != 4 || m.Cols() != 1 {
+		t.Fatalf("From(%v) has %dx%d elements, want 4x1", v, m.Rows(), m.Cols())
+	}
+	for row := 0; row < 4; row++ {
+		if m.Get(0, row) != v[row] {
+			t.Errorf("From(%v).Get(0, %d) = %f, want %f", v, row, m.Get(0, row), v[row])
+		}
+	}
+}
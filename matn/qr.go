@@ -0,0 +1,177 @@
+package matn
+
+import (
+	"github.com/barnex/fmath"
+)
+
+// QR is the QR decomposition A = Q*R of a matrix with at least
+// as many rows as columns computed with Householder reflections.
+type QR struct {
+	// qr holds the Householder vectors below the diagonal
+	// and R without its diagonal above.
+	qr    *T
+	rDiag []float32
+}
+
+// QR returns the QR decomposition of the matrix.
+// It panics if the matrix has fewer rows than columns.
+func (self *T) QR() *QR {
+	m, n := self.rows, self.cols
+	if m < n {
+		panic("matn: QR needs at least as many rows as columns")
+	}
+	a := self.Copy()
+	result := &QR{qr: a, rDiag: make([]float32, n)}
+
+	for k := 0; k < n; k++ {
+		colK := a.Col(k)
+		nrm := norm(colK[k:])
+		if nrm != 0 {
+			if colK[k] < 0 {
+				nrm = -nrm
+			}
+			for i := k; i < m; i++ {
+				colK[i] /= nrm
+			}
+			colK[k] += 1
+			for j := k + 1; j < n; j++ {
+				colJ := a.Col(j)
+				var s float32
+				for i := k; i < m; i++ {
+					s += colK[i] * colJ[i]
+				}
+				s = -s / colK[k]
+				for i := k; i < m; i++ {
+					colJ[i] += s * colK[i]
+				}
+			}
+		}
+		result.rDiag[k] = -nrm
+	}
+	return result
+}
+
+// machineEpsilon is the difference between 1 and the next larger number.
+const machineEpsilon = 1.1920929e-07 //gend:float64 2.220446049250313e-16
+
+// IsFullRank returns true if the columns of the decomposed matrix are linearly independent.
+// Diagonal elements of R that are negligible relative to the largest one
+// within the rounding errors of the decomposition count as zero.
+func (self *QR) IsFullRank() bool {
+	var max float32
+	for _, d := range self.rDiag {
+		if d := fmath.Abs(d); d > max {
+			max = d
+		}
+	}
+	tolerance := max * machineEpsilon * float32(self.qr.rows)
+	for _, d := range self.rDiag {
+		if fmath.Abs(d) <= tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// Q returns the orthonormal factor with the rows of A and the columns of R.
+func (self *QR) Q() *T {
+	m, n := self.qr.rows, self.qr.cols
+	q := New(m, n)
+	for k := n - 1; k >= 0; k-- {
+		colK := self.qr.Col(k)
+		q.data[k*m+k] = 1
+		for j := k; j < n; j++ {
+			if colK[k] == 0 {
+				continue
+			}
+			colJ := q.Col(j)
+			var s float32
+			for i := k; i < m; i++ {
+				s += colK[i] * colJ[i]
+			}
+			s = -s / colK[k]
+			for i := k; i < m; i++ {
+				colJ[i] += s * colK[i]
+			}
+		}
+	}
+	return q
+}
+
+// R returns the square upper triangular factor.
+func (self *QR) R() *T {
+	n := self.qr.cols
+	r := New(n, n)
+	for col := 0; col < n; col++ {
+		for row := 0; row < col; row++ {
+			r.data[col*n+row] = self.qr.data[col*self.qr.rows+row]
+		}
+		r.data[col*n+col] = self.rDiag[col]
+	}
+	return r
+}
+
+// Solve returns the X minimizing the norm of A*X - b for every column of b.
+// If A is rank deficient ErrSingular is returned.
+func (self *QR) Solve(b *T) (*T, error) {
+	m, n := self.qr.rows, self.qr.cols
+	if b.rows != m {
+		panic("matn: right hand side has wrong number of rows")
+	}
+	if !self.IsFullRank() {
+		return nil, ErrSingular
+	}
+	x := New(n, b.cols)
+	v := make([]float32, m)
+	for col := 0; col < b.cols; col++ {
+		copy(v, b.Col(col))
+		// Compute Q^T * b
+		for k := 0; k < n; k++ {
+			colK := self.qr.Col(k)
+			var s float32
+			for i := k; i < m; i++ {
+				s += colK[i] * v[i]
+			}
+			s = -s / colK[k]
+			for i := k; i < m; i++ {
+				v[i] += s * colK[i]
+			}
+		}
+		// Solve R * x = Q^T * b
+		for k := n - 1; k >= 0; k-- {
+			v[k] /= self.rDiag[k]
+			colK := self.qr.Col(k)
+			for i := 0; i < k; i++ {
+				v[i] -= v[k] * colK[i]
+			}
+		}
+		copy(x.Col(col), v[:n])
+	}
+	return x, nil
+}
+
+// LeastSquares returns the X minimizing the norm of a*X - b
+// for every column of b. a must have at least as many rows as columns.
+// If a is rank deficient ErrSingular is returned.
+func LeastSquares(a, b *T) (*T, error) {
+	return a.QR().Solve(b)
+}
+
+// norm returns the euclidean norm of v avoiding overflow and underflow.
+func norm(v []float32) float32 {
+	var max float32
+	for _, x := range v {
+		if x := fmath.Abs(x); x > max {
+			max = x
+		}
+	}
+	if max == 0 {
+		return 0
+	}
+	var sum float32
+	for _, x := range v {
+		x /= max
+		sum += x * x
+	}
+	return max * fmath.Sqrt(sum)
+}
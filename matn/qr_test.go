@@ -0,0 +1,76 @@
+package matn
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestQR(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for n := 1; n <= 6; n++ {
+		for m := n; m <= n+4; m++ {
+			a := randomMatrix(r, m, n)
+			qr := a.QR()
+			q, rr := qr.Q(), qr.R()
+			if d := maxAbsDiff(Mul(q, rr), a); d > testEpsilon {
+				t.Fatalf("Q*R-A of %v is %f", a, d)
+			}
+			if d := maxAbsDiff(Mul(q.Transposed(), q), NewIdent(n)); d > testEpsilon {
+				t.Fatalf("Q^T*Q of %v is not the identity: %f", a, d)
+			}
+			for col := 0; col < n; col++ {
+				for row := col + 1; row < n; row++ {
+					if rr.Get(col, row) != 0 {
+						t.Fatalf("R of %v is not upper triangular: %v", a, rr)
+					}
+				}
+			}
+			if !qr.IsFullRank() {
+				t.Fatalf("random matrix %v is not full rank", a)
+			}
+		}
+	}
+}
+
+func TestLeastSquares(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for n := 1; n <= 6; n++ {
+		for m := n; m <= 3*n; m++ {
+			a := randomMatrix(r, m, n)
+			b := randomMatrix(r, m, 2)
+			x, err := LeastSquares(a, b)
+			if err != nil {
+				t.Fatalf("LeastSquares(%v, %v) returned %v", a, b, err)
+			}
+			// The residual of the minimum is orthogonal to the columns of A.
+			residual := Mul(a, x).Sub(b)
+			if d := maxAbsDiff(Mul(a.Transposed(), residual), New(n, 2)); d > testEpsilon {
+				t.Fatalf("A^T*(A*x-b) of LeastSquares(%v, %v) is %f", a, b, d)
+			}
+			if m == n {
+				if d := maxAbsDiff(Mul(a, x), b); d > testEpsilon {
+					t.Fatalf("A*x-b of the square LeastSquares(%v, %v) is %f", a, b, d)
+				}
+			}
+		}
+	}
+}
+
+func TestLeastSquaresRankDeficient(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	combined := randomMatrix(r, 6, 3)
+	// The third column is a combination of the first two.
+	for row := 0; row < 6; row++ {
+		combined.Set(2, row, 2*combined.Get(0, row)-combined.Get(1, row))
+	}
+	zeroCol := randomMatrix(r, 5, 3)
+	copy(zeroCol.Col(1), make([]float32, 5))
+	for _, a := range []*T{combined, zeroCol, New(4, 2)} {
+		if a.QR().IsFullRank() {
+			t.Errorf("rank deficient %v is full rank", a)
+		}
+		if _, err := LeastSquares(a, randomMatrix(r, a.Rows(), 1)); err != ErrSingular {
+			t.Errorf("LeastSquares(%v) returned %v, want ErrSingular", a, err)
+		}
+	}
+}
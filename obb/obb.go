@@ -0,0 +1,265 @@
+// The package obb contains a float32 oriented bounding box.
+package obb
+
+import (
+	"fmt"
+
+	"github.com/barnex/fmath"
+	"github.com/ungerik/go3d/mat3x3"
+	"github.com/ungerik/go3d/mat4x4"
+	"github.com/ungerik/go3d/vec3"
+)
+
+// epsilon is added to the absolute rotation terms of the separating axis test
+// to counteract arithmetic errors when two edges are nearly parallel.
+const epsilon = 1e-6 //gend:float64 1e-12
+
+// T is an oriented bounding box.
+// The columns of Axes are the orthonormal local X, Y and Z axes of the box
+// and HalfExtents is the half size of the box along these axes.
+type T struct {
+	Center      vec3.T
+	Axes        mat3x3.T
+	HalfExtents vec3.T
+}
+
+// FromBox returns the oriented bounding box equal to box.
+func FromBox(box *vec3.Box) T {
+	return T{box.Center(), mat3x3.Ident, box.Extents()}
+}
+
+// FromPoints fits an oriented bounding box to points
+// by aligning the axes with the principal components of the points.
+func FromPoints(points []vec3.T) T {
+	if len(points) == 0 {
+		return T{Axes: mat3x3.Ident}
+	}
+
+	var mean vec3.T
+	for i := range points {
+		mean.Add(&points[i])
+	}
+	mean.Scale(1 / float32(len(points)))
+
+	var covariance mat3x3.T
+	for i := range points {
+		d := vec3.Sub(&points[i], &mean)
+		for col := 0; col < 3; col++ {
+			for row := 0; row < 3; row++ {
+				covariance[col][row] += d[col] * d[row]
+			}
+		}
+	}
+
+	_, axes := covariance.SymmetricEigen()
+
+	min := vec3.MaxVal
+	max := vec3.MinVal
+	for i := range points {
+		d := vec3.Sub(&points[i], &mean)
+		for j := 0; j < 3; j++ {
+			p := vec3.Dot(&d, &axes[j])
+			if p < min[j] {
+				min[j] = p
+			}
+			if p > max[j] {
+				max[j] = p
+			}
+		}
+	}
+
+	self := T{Center: mean, Axes: axes}
+	for j := 0; j < 3; j++ {
+		offset := axes[j].Scaled((min[j] + max[j]) * 0.5)
+		self.Center.Add(&offset)
+		self.HalfExtents[j] = (max[j] - min[j]) * 0.5
+	}
+	return self
+}
+
+// Parse parses T from a string. See also String()
+func Parse(s string) (r T, err error) {
+	_, err = fmt.Sscanf(s,
+		"%f %f %f %f %f %f %f %f %f %f %f %f %f %f %f",
+		&r.Center[0], &r.Center[1], &r.Center[2],
+		&r.Axes[0][0], &r.Axes[0][1], &r.Axes[0][2],
+		&r.Axes[1][0], &r.Axes[1][1], &r.Axes[1][2],
+		&r.Axes[2][0], &r.Axes[2][1], &r.Axes[2][2],
+		&r.HalfExtents[0], &r.HalfExtents[1], &r.HalfExtents[2],
+	)
+	return r, err
+}
+
+// String formats T as string. See also Parse().
+func (self *T) String() string {
+	return fmt.Sprintf("%s %s %s", self.Center.String(), self.Axes.String(), self.HalfExtents.String())
+}
+
+// Transform transforms the box by the matrix m and returns self.
+// m must consist only of rotation, scaling and translation,
+// because with shearing the result would no longer be a box.
+func (self *T) Transform(m *mat4x4.T) *T {
+	self.Center = m.MulVec3(&self.Center)
+	for i := 0; i < 3; i++ {
+		a := &self.Axes[i]
+		axis := vec3.T{
+			m[0][0]*a[0] + m[1][0]*a[1] + m[2][0]*a[2],
+			m[0][1]*a[0] + m[1][1]*a[1] + m[2][1]*a[2],
+			m[0][2]*a[0] + m[1][2]*a[1] + m[2][2]*a[2],
+		}
+		scale := axis.Length()
+		if scale != 0 {
+			axis.Scale(1 / scale)
+		}
+		self.Axes[i] = axis
+		self.HalfExtents[i] *= scale
+	}
+	return self
+}
+
+// Transformed returns a copy of the box transformed by the matrix m.
+func (self *T) Transformed(m *mat4x4.T) T {
+	r := *self
+	return *r.Transform(m)
+}
+
+// localPoint returns p in the coordinate system of the box.
+func (self *T) localPoint(p *vec3.T) vec3.T {
+	d := vec3.Sub(p, &self.Center)
+	return vec3.T{vec3.Dot(&d, &self.Axes[0]), vec3.Dot(&d, &self.Axes[1]), vec3.Dot(&d, &self.Axes[2])}
+}
+
+// ContainsPoint returns true if p is inside of the box or on its surface.
+func (self *T) ContainsPoint(p *vec3.T) bool {
+	l := self.localPoint(p)
+	return fmath.Abs(l[0]) <= self.HalfExtents[0] &&
+		fmath.Abs(l[1]) <= self.HalfExtents[1] &&
+		fmath.Abs(l[2]) <= self.HalfExtents[2]
+}
+
+// ClosestPoint returns the point inside or on the surface of the box
+// that is closest to p.
+func (self *T) ClosestPoint(p *vec3.T) vec3.T {
+	l := self.localPoint(p)
+	r := self.Center
+	for i := 0; i < 3; i++ {
+		d := l[i]
+		if d > self.HalfExtents[i] {
+			d = self.HalfExtents[i]
+		} else if d < -self.HalfExtents[i] {
+			d = -self.HalfExtents[i]
+		}
+		offset := self.Axes[i].Scaled(d)
+		r.Add(&offset)
+	}
+	return r
+}
+
+// DistanceToPoint returns the distance from p to the box.
+// Points inside of the box have a distance of zero.
+func (self *T) DistanceToPoint(p *vec3.T) float32 {
+	c := self.ClosestPoint(p)
+	d := vec3.Sub(p, &c)
+	return d.Length()
+}
+
+// Support returns the corner of the box that is farthest in direction dir.
+func (self *T) Support(dir *vec3.T) vec3.T {
+	s := self.Center
+	for i := 0; i < 3; i++ {
+		offset := self.Axes[i].Scaled(self.HalfExtents[i])
+		if vec3.Dot(&self.Axes[i], dir) < 0 {
+			offset.Invert()
+		}
+		s.Add(&offset)
+	}
+	return s
+}
+
+// Corners returns the eight corner points of the box.
+// Bit 0 of the index selects the positive over the negative X half extent,
+// bit 1 selects it for Y and bit 2 for Z, like vec3.Box.Corners.
+func (self *T) Corners() [8]vec3.T {
+	var corners [8]vec3.T
+	for i := range corners {
+		corners[i] = self.Center
+		for j := 0; j < 3; j++ {
+			h := self.HalfExtents[j]
+			if i&(1<<uint(j)) == 0 {
+				h = -h
+			}
+			offset := self.Axes[j].Scaled(h)
+			corners[i].Add(&offset)
+		}
+	}
+	return corners
+}
+
+// Bounds returns the smallest axis aligned box that contains the box.
+func (self *T) Bounds() vec3.Box {
+	var extents vec3.T
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			extents[j] += fmath.Abs(self.Axes[i][j]) * self.HalfExtents[i]
+		}
+	}
+	return vec3.Box{
+		Min: vec3.Sub(&self.Center, &extents),
+		Max: vec3.Add(&self.Center, &extents),
+	}
+}
+
+// Intersects returns true if self and other overlap or touch
+// using the separating axis test with the 15 potential separating axes.
+func (self *T) Intersects(other *T) bool {
+	a, b := self, other
+	var r, absR mat3x3.T
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r[i][j] = vec3.Dot(&a.Axes[i], &b.Axes[j])
+			absR[i][j] = fmath.Abs(r[i][j]) + epsilon
+		}
+	}
+	d := vec3.Sub(&b.Center, &a.Center)
+	t := vec3.T{vec3.Dot(&d, &a.Axes[0]), vec3.Dot(&d, &a.Axes[1]), vec3.Dot(&d, &a.Axes[2])}
+	ea, eb := &a.HalfExtents, &b.HalfExtents
+
+	// The axes of self.
+	for i := 0; i < 3; i++ {
+		ra := ea[i]
+		rb := eb[0]*absR[i][0] + eb[1]*absR[i][1] + eb[2]*absR[i][2]
+		if fmath.Abs(t[i]) > ra+rb {
+			return false
+		}
+	}
+
+	// The axes of other.
+	for j := 0; j < 3; j++ {
+		ra := ea[0]*absR[0][j] + ea[1]*absR[1][j] + ea[2]*absR[2][j]
+		rb := eb[j]
+		if fmath.Abs(t[0]*r[0][j]+t[1]*r[1][j]+t[2]*r[2][j]) > ra+rb {
+			return false
+		}
+	}
+
+	// The cross products of the axes of self and other.
+	for i := 0; i < 3; i++ {
+		i1, i2 := (i+1)%3, (i+2)%3
+		for j := 0; j < 3; j++ {
+			j1, j2 := (j+1)%3, (j+2)%3
+			ra := ea[i1]*absR[i2][j] + ea[i2]*absR[i1][j]
+			rb := eb[j1]*absR[i][j2] + eb[j2]*absR[i][j1]
+			if fmath.Abs(t[i2]*r[i1][j]-t[i1]*r[i2][j]) > ra+rb {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IntersectsBox returns true if the oriented box and the axis aligned box
+// overlap or touch.
+func (self *T) IntersectsBox(box *vec3.Box) bool {
+	other := FromBox(box)
+	return self.Intersects(&other)
+}
@@ -0,0 +1,206 @@
+package obb
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/barnex/fmath"
+	"github.com/ungerik/go3d/gjk"
+	"github.com/ungerik/go3d/mat3x3"
+	"github.com/ungerik/go3d/quaternion"
+	"github.com/ungerik/go3d/vec3"
+)
+
+// testEpsilon is the tolerance of the distance and containment tests.
+const testEpsilon = 1e-4 //gend:float64 1e-9
+
+func random(r *rand.Rand, min, max float32) float32 {
+	return min + float32(r.Float64())*(max-min)
+}
+
+func randomPoint(r *rand.Rand, min, max float32) vec3.T {
+	return vec3.T{random(r, min, max), random(r, min, max), random(r, min, max)}
+}
+
+func randomRotation(r *rand.Rand) mat3x3.T {
+	axis := randomPoint(r, -1, 1)
+	for axis.LengthSqr() == 0 {
+		axis = randomPoint(r, -1, 1)
+	}
+	axis.Normalize()
+	q := quaternion.FromAxisAngle(&axis, random(r, -math.Pi, math.Pi))
+	var m mat3x3.T
+	m.AssignQuaternion(&q)
+	return m
+}
+
+func randomBox(r *rand.Rand) T {
+	return T{Center: randomPoint(r, -2, 2), Axes: randomRotation(r), HalfExtents: randomPoint(r, 0.1, 1.5)}
+}
+
+func corners(box *T) gjk.Points {
+	c := box.Corners()
+	return gjk.Points(c[:])
+}
+
+func TestIntersects(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 5000; n++ {
+		a, b := randomBox(r), randomBox(r)
+		distance, _, _ := gjk.Distance(corners(&a), corners(&b))
+		// Skip boxes that nearly touch, where the tolerances of both tests differ.
+		if distance > 0 && distance < testEpsilon {
+			continue
+		}
+		want := distance == 0
+		if a.Intersects(&b) != want || b.Intersects(&a) != want {
+			t.Fatalf("Intersects(%v, %v) = %v, want %v with the distance %f", a, b, !want, want, distance)
+		}
+
+		box := vec3.Box{Min: randomPoint(r, -3, 1)}
+		size := randomPoint(r, 0.1, 2)
+		box.Max = vec3.Add(&box.Min, &size)
+		aligned := FromBox(&box)
+		distance, _, _ = gjk.Distance(corners(&a), corners(&aligned))
+		if distance > 0 && distance < testEpsilon {
+			continue
+		}
+		if want := distance == 0; a.IntersectsBox(&box) != want {
+			t.Fatalf("IntersectsBox(%v, %v) = %v, want %v with the distance %f", a, box, !want, want, distance)
+		}
+	}
+}
+
+func TestIntersectsEdges(t *testing.T) {
+	// A cube rotated by 45 degrees around Z has an edge along Z at X = sqrt(2)
+	// and a cube rotated around Y has one along Y at X = -sqrt(2).
+	// Only the cross product of the edges, the X axis, separates them.
+	var axesA, axesB mat3x3.T
+	axesA.AssignZRotation(math.Pi / 4)
+	axesB.AssignYRotation(math.Pi / 4)
+	a := T{Axes: axesA, HalfExtents: vec3.T{1, 1, 1}}
+	b := T{Axes: axesB, HalfExtents: vec3.T{1, 1, 1}}
+	for _, gap := range []float32{-0.1, 0.1} {
+		b.Center = vec3.T{2*fmath.Sqrt(2) + gap, 0, 0}
+		if want := gap < 0; a.Intersects(&b) != want {
+			t.Errorf("Intersects(%v, %v) = %v, want %v", a, b, !want, want)
+		}
+	}
+}
+
+// checkOrthonormal checks that the columns of m are orthonormal.
+func checkOrthonormal(t *testing.T, m *mat3x3.T) {
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			want := float32(0)
+			if i == j {
+				want = 1
+			}
+			if d := vec3.Dot(&m[i], &m[j]); fmath.Abs(d-want) > testEpsilon {
+				t.Fatalf("the axes %v are not orthonormal", m)
+			}
+		}
+	}
+}
+
+func checkFromPoints(t *testing.T, name string, points []vec3.T) {
+	box := FromPoints(points)
+	checkOrthonormal(t, &box.Axes)
+	var touching [3][2]bool
+	for i := range points {
+		l := box.localPoint(&points[i])
+		for j := 0; j < 3; j++ {
+			tolerance := testEpsilon * (1 + box.HalfExtents[j] + fmath.Abs(l[j]))
+			if fmath.Abs(l[j]) > box.HalfExtents[j]+tolerance {
+				t.Fatalf("%s: FromPoints box %v does not contain the point %d %v", name, box, i, points[i])
+			}
+			// The box must be tight along its axes.
+			if l[j] >= box.HalfExtents[j]-tolerance {
+				touching[j][1] = true
+			}
+			if l[j] <= -box.HalfExtents[j]+tolerance {
+				touching[j][0] = true
+			}
+		}
+	}
+	if touching != [3][2]bool{{true, true}, {true, true}, {true, true}} {
+		t.Errorf("%s: not every side of the FromPoints box %v touches a point", name, box)
+	}
+}
+
+func TestFromPoints(t *testing.T) {
+	if box := FromPoints(nil); box != (T{Axes: mat3x3.Ident}) {
+		t.Errorf("FromPoints(nil) = %v", box)
+	}
+	checkFromPoints(t, "one point", []vec3.T{{1, 2, 3}})
+	checkFromPoints(t, "duplicates", []vec3.T{{1, 2, 3}, {1, 2, 3}, {4, 5, 6}, {4, 5, 6}})
+
+	r := rand.New(rand.NewSource(2))
+	for n := 0; n < 200; n++ {
+		// Points of a random rotated and stretched box.
+		source := randomBox(r)
+		source.HalfExtents = vec3.T{random(r, 0, 5), random(r, 0, 1), random(r, 0, 0.2)}
+		points := make([]vec3.T, 2+r.Intn(100))
+		for i := range points {
+			l := randomPoint(r, -1, 1)
+			points[i] = source.Center
+			for j := 0; j < 3; j++ {
+				offset := source.Axes[j].Scaled(l[j] * source.HalfExtents[j])
+				points[i].Add(&offset)
+			}
+		}
+		checkFromPoints(t, "random", points)
+
+		// Collinear points.
+		for i := range points {
+			offset := source.Axes[0].Scaled(random(r, -3, 3))
+			points[i] = vec3.Add(&source.Center, &offset)
+		}
+		checkFromPoints(t, "collinear", points)
+
+		// Coplanar points.
+		for i := range points {
+			u, v := source.Axes[0].Scaled(random(r, -3, 3)), source.Axes[1].Scaled(random(r, -1, 1))
+			points[i] = vec3.Add(&source.Center, &u)
+			points[i].Add(&v)
+		}
+		checkFromPoints(t, "coplanar", points)
+	}
+}
+
+func TestClosestPoint(t *testing.T) {
+	// A cube rotated by 45 degrees around Z has an edge at X = sqrt(2).
+	var axes mat3x3.T
+	axes.AssignZRotation(math.Pi / 4)
+	box := T{Center: vec3.T{0, 0, 1}, Axes: axes, HalfExtents: vec3.T{1, 1, 1}}
+	p := vec3.T{2, 0, 1.5}
+	if c := box.ClosestPoint(&p); fmath.Abs(c[0]-fmath.Sqrt(2)) > testEpsilon || fmath.Abs(c[1]) > testEpsilon || fmath.Abs(c[2]-1.5) > testEpsilon {
+		t.Errorf("ClosestPoint(%v) = %v, want the point on the edge %f 0 1.5", p, c, fmath.Sqrt(2))
+	}
+	if d := box.DistanceToPoint(&p); fmath.Abs(d-(2-fmath.Sqrt(2))) > testEpsilon {
+		t.Errorf("DistanceToPoint(%v) = %f, want %f", p, d, 2-fmath.Sqrt(2))
+	}
+	p = vec3.T{0.5, 0.5, 0.5}
+	c := box.ClosestPoint(&p)
+	if d := vec3.Sub(&c, &p); d.Length() > testEpsilon || box.DistanceToPoint(&p) > testEpsilon {
+		t.Errorf("ClosestPoint(%v) of a point inside = %v, want the point itself", p, c)
+	}
+
+	r := rand.New(rand.NewSource(3))
+	for n := 0; n < 1000; n++ {
+		box := randomBox(r)
+		p := randomPoint(r, -4, 4)
+		c := box.ClosestPoint(&p)
+		l := box.localPoint(&c)
+		for j := 0; j < 3; j++ {
+			if fmath.Abs(l[j]) > box.HalfExtents[j]+testEpsilon {
+				t.Fatalf("ClosestPoint(%v) = %v is outside of %v", p, c, box)
+			}
+		}
+		want, _, _ := gjk.Distance(corners(&box), gjk.Points{p})
+		if d := box.DistanceToPoint(&p); fmath.Abs(d-want) > testEpsilon*10 {
+			t.Fatalf("DistanceToPoint(%v) of %v = %f, want %f", p, box, d, want)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// The package octree contains a float32 loose octree of vec3.Box items.
+//
+// Every node of a loose octree has bounds that are twice the size of its cell,
+// so an item is stored in the deepest node whose cell contains the center
+// of the item and whose cell size is at least the size of the item.
+// This makes inserting, removing and moving items cheap
+// which suits scenes with many moving objects.
+//
+// Items are identified by integer IDs chosen by the user.
+// A T is not safe for concurrent use.
+package octree
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/ungerik/go3d/vec3"
+)
+
+// T is a loose octree.
+type T struct {
+	root     *node
+	maxDepth int
+	entries  map[int]*entry
+}
+
+type node struct {
+	parent   *node
+	octant   int
+	center   vec3.T
+	halfSize float32
+	children [8]*node
+	entries  []*entry
+	// count is the number of entries in the subtree.
+	count int
+}
+
+type entry struct {
+	id   int
+	box  vec3.Box
+	node *node
+}
+
+// New returns an empty octree covering bounds that subdivides up to maxDepth levels.
+// Items outside of bounds can be inserted, but are kept in the root node.
+func New(bounds *vec3.Box, maxDepth int) *T {
+	size := bounds.Size()
+	halfSize := size[0]
+	if size[1] > halfSize {
+		halfSize = size[1]
+	}
+	if size[2] > halfSize {
+		halfSize = size[2]
+	}
+	return &T{
+		root:     &node{center: bounds.Center(), halfSize: halfSize * 0.5},
+		maxDepth: maxDepth,
+		entries:  make(map[int]*entry),
+	}
+}
+
+// String returns a description of the octree.
+func (self *T) String() string {
+	return fmt.Sprintf("octree with %d items", len(self.entries))
+}
+
+// Len returns the number of items.
+func (self *T) Len() int {
+	return len(self.entries)
+}
+
+// Box returns the box of the item with id.
+func (self *T) Box(id int) (box vec3.Box, ok bool) {
+	e, ok := self.entries[id]
+	if !ok {
+		return vec3.Box{}, false
+	}
+	return e.box, true
+}
+
+// Insert adds an item with id and box.
+// An existing item with the same id is moved to box.
+func (self *T) Insert(id int, box *vec3.Box) {
+	if self.Move(id, box) {
+		return
+	}
+	e := &entry{id: id, box: *box}
+	self.entries[id] = e
+	self.add(e, self.locate(box))
+}
+
+// Remove removes the item with id and returns if it existed.
+func (self *T) Remove(id int) bool {
+	e, ok := self.entries[id]
+	if !ok {
+		return false
+	}
+	delete(self.entries, id)
+	self.remove(e, e.node)
+	return true
+}
+
+// Move changes the box of the item with id and returns if it existed.
+func (self *T) Move(id int, box *vec3.Box) bool {
+	e, ok := self.entries[id]
+	if !ok {
+		return false
+	}
+	e.box = *box
+	if n := self.locate(box); n != e.node {
+		// Add before removing so that the new nodes
+		// created by locate are not released as empty.
+		old := e.node
+		self.add(e, n)
+		self.remove(e, old)
+	}
+	return true
+}
+
+// QueryBox appends the IDs of all items whose boxes
+// overlap or touch box to result and returns it.
+func (self *T) QueryBox(box *vec3.Box, result []int) []int {
+	return self.root.query(func(b *vec3.Box) bool { return b.Intersects(box) }, true, result)
+}
+
+// QueryRadius appends the IDs of all items whose boxes
+// are within radius of center to result and returns it.
+func (self *T) QueryRadius(center *vec3.T, radius float32, result []int) []int {
+	radiusSqr := radius * radius
+	return self.root.query(func(b *vec3.Box) bool { return b.DistanceToPointSqr(center) <= radiusSqr }, true, result)
+}
+
+// Nearest appends the IDs of the k items whose boxes are nearest
+// to point to result, ordered by increasing distance, and returns it.
+func (self *T) Nearest(point *vec3.T, k int, result []int) []int {
+	if k <= 0 || len(self.entries) == 0 {
+		return result
+	}
+	queue := priorityQueue{{node: self.root}}
+	found := 0
+	for len(queue) > 0 && found < k {
+		c := heap.Pop(&queue).(candidate)
+		if c.entry != nil {
+			result = append(result, c.entry.id)
+			found++
+			continue
+		}
+		for _, e := range c.node.entries {
+			heap.Push(&queue, candidate{distanceSqr: e.box.DistanceToPointSqr(point), entry: e})
+		}
+		for _, child := range c.node.children {
+			if child != nil {
+				bounds := child.looseBounds()
+				heap.Push(&queue, candidate{distanceSqr: bounds.DistanceToPointSqr(point), node: child})
+			}
+		}
+	}
+	return result
+}
+
+// locate returns the node where box belongs, creating missing nodes on the way.
+func (self *T) locate(box *vec3.Box) *node {
+	n := self.root
+	center := box.Center()
+	extents := box.Extents()
+	maxExtent := extents[0]
+	if extents[1] > maxExtent {
+		maxExtent = extents[1]
+	}
+	if extents[2] > maxExtent {
+		maxExtent = extents[2]
+	}
+	if !n.cellContains(&center) {
+		return n
+	}
+	for depth := 0; depth < self.maxDepth; depth++ {
+		childHalfSize := n.halfSize * 0.5
+		// The loose bounds of a child reach childHalfSize beyond its cell.
+		if maxExtent > childHalfSize {
+			break
+		}
+		octant := 0
+		for i := 0; i < 3; i++ {
+			if center[i] >= n.center[i] {
+				octant |= 1 << uint(i)
+			}
+		}
+		child := n.children[octant]
+		if child == nil {
+			child = &node{parent: n, octant: octant, center: n.center, halfSize: childHalfSize}
+			for i := 0; i < 3; i++ {
+				if octant&(1<<uint(i)) != 0 {
+					child.center[i] += childHalfSize
+				} else {
+					child.center[i] -= childHalfSize
+				}
+			}
+			n.children[octant] = child
+		}
+		n = child
+	}
+	return n
+}
+
+func (self *T) add(e *entry, n *node) {
+	e.node = n
+	n.entries = append(n.entries, e)
+	for ; n != nil; n = n.parent {
+		n.count++
+	}
+}
+
+func (self *T) remove(e *entry, n *node) {
+	for i, other := range n.entries {
+		if other == e {
+			last := len(n.entries) - 1
+			n.entries[i] = n.entries[last]
+			n.entries[last] = nil
+			n.entries = n.entries[:last]
+			break
+		}
+	}
+	// Decrement the counts up to the root and release empty subtrees.
+	for ; n != nil; n = n.parent {
+		n.count--
+		if n.count == 0 && n.parent != nil {
+			n.parent.children[n.octant] = nil
+		}
+	}
+}
+
+func (self *node) cellContains(p *vec3.T) bool {
+	for i := 0; i < 3; i++ {
+		if p[i] < self.center[i]-self.halfSize || p[i] > self.center[i]+self.halfSize {
+			return false
+		}
+	}
+	return true
+}
+
+func (self *node) looseBounds() vec3.Box {
+	s := 2 * self.halfSize
+	return vec3.Box{
+		Min: vec3.T{self.center[0] - s, self.center[1] - s, self.center[2] - s},
+		Max: vec3.T{self.center[0] + s, self.center[1] + s, self.center[2] + s},
+	}
+}
+
+// query appends the entries matching test. The root is never culled
+// because it may contain items outside of its loose bounds.
+func (self *node) query(test func(b *vec3.Box) bool, isRoot bool, result []int) []int {
+	if !isRoot {
+		bounds := self.looseBounds()
+		if !test(&bounds) {
+			return result
+		}
+	}
+	for _, e := range self.entries {
+		if test(&e.box) {
+			result = append(result, e.id)
+		}
+	}
+	for _, child := range self.children {
+		if child != nil {
+			result = child.query(test, false, result)
+		}
+	}
+	return result
+}
+
+type candidate struct {
+	distanceSqr float32
+	node        *node
+	entry       *entry
+}
+
+// priorityQueue implements heap.Interface with the nearest candidate first.
+type priorityQueue []candidate
+
+func (self priorityQueue) Len() int {
+	return len(self)
+}
+
+func (self priorityQueue) Less(i, j int) bool {
+	return self[i].distanceSqr < self[j].distanceSqr
+}
+
+func (self priorityQueue) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+func (self *priorityQueue) Push(x interface{}) {
+	*self = append(*self, x.(candidate))
+}
+
+func (self *priorityQueue) Pop() interface{} {
+	old := *self
+	c := old[len(old)-1]
+	*self = old[:len(old)-1]
+	return c
+}
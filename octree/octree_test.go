@@ -0,0 +1,162 @@
+package octree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ungerik/go3d/vec3"
+)
+
+func random(r *rand.Rand, min, max float32) float32 {
+	return min + float32(r.Float64())*(max-min)
+}
+
+func randomPoint(r *rand.Rand, min, max float32) vec3.T {
+	return vec3.T{random(r, min, max), random(r, min, max), random(r, min, max)}
+}
+
+// randomBox returns boxes of very different sizes,
+// some of them outside of the bounds -10..10 of the tests.
+func randomBox(r *rand.Rand) vec3.Box {
+	center := randomPoint(r, -15, 15)
+	extents := randomPoint(r, 0, 0.1)
+	if r.Intn(4) == 0 {
+		extents = randomPoint(r, 0, 8)
+	}
+	return vec3.Box{Min: vec3.Sub(&center, &extents), Max: vec3.Add(&center, &extents)}
+}
+
+func sortedIDs(ids []int) []int {
+	sort.Ints(ids)
+	return ids
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// check compares the queries of tree with a linear scan of items.
+func check(t *testing.T, tree *T, items map[int]vec3.Box, r *rand.Rand) {
+	if tree.Len() != len(items) {
+		t.Fatalf("Len() = %d, want %d", tree.Len(), len(items))
+	}
+	for id, want := range items {
+		if box, ok := tree.Box(id); !ok || box != want {
+			t.Fatalf("Box(%d) = %v, %v, want %v", id, box, ok, want)
+		}
+	}
+
+	var result []int
+	for q := 0; q < 20; q++ {
+		box := randomBox(r)
+		var want []int
+		for id := range items {
+			b := items[id]
+			if b.Intersects(&box) {
+				want = append(want, id)
+			}
+		}
+		result = tree.QueryBox(&box, result[:0])
+		if !equalIDs(sortedIDs(result), sortedIDs(want)) {
+			t.Fatalf("QueryBox(%v) = %v, want %v", box, result, want)
+		}
+
+		center := randomPoint(r, -15, 15)
+		radius := random(r, 0, 5)
+		want = want[:0]
+		for id := range items {
+			b := items[id]
+			if b.DistanceToPointSqr(&center) <= radius*radius {
+				want = append(want, id)
+			}
+		}
+		result = tree.QueryRadius(&center, radius, result[:0])
+		if !equalIDs(sortedIDs(result), sortedIDs(want)) {
+			t.Fatalf("QueryRadius(%v, %f) = %v, want %v", center, radius, result, want)
+		}
+
+		distances := make([]float32, 0, len(items))
+		for id := range items {
+			b := items[id]
+			distances = append(distances, b.DistanceToPointSqr(&center))
+		}
+		sort.Slice(distances, func(i, j int) bool { return distances[i] < distances[j] })
+		for _, k := range []int{0, 1, 5, len(items), len(items) + 3} {
+			result = tree.Nearest(&center, k, result[:0])
+			wantLen := k
+			if wantLen > len(items) {
+				wantLen = len(items)
+			}
+			if len(result) != wantLen {
+				t.Fatalf("Nearest(%v, %d) returned %d items, want %d", center, k, len(result), wantLen)
+			}
+			seen := make(map[int]bool)
+			for i, id := range result {
+				b, ok := items[id]
+				if !ok || seen[id] {
+					t.Fatalf("Nearest(%v, %d) returned the unknown or duplicate id %d", center, k, id)
+				}
+				seen[id] = true
+				if d := b.DistanceToPointSqr(&center); d != distances[i] {
+					t.Fatalf("Nearest(%v, %d)[%d] has distance %f, want %f", center, k, i, d, distances[i])
+				}
+			}
+		}
+	}
+}
+
+func TestRandomOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := New(&vec3.Box{Min: vec3.T{-10, -10, -10}, Max: vec3.T{10, 10, 10}}, 6)
+	items := make(map[int]vec3.Box)
+	check(t, tree, items, r)
+	for round := 0; round < 50; round++ {
+		for n := 0; n < 40; n++ {
+			id := r.Intn(200)
+			_, exists := items[id]
+			switch r.Intn(4) {
+			case 0, 1:
+				// Inserting an existing id moves the item.
+				box := randomBox(r)
+				tree.Insert(id, &box)
+				items[id] = box
+			case 2:
+				if tree.Remove(id) != exists {
+					t.Fatalf("Remove(%d) = %v, want %v", id, !exists, exists)
+				}
+				delete(items, id)
+			case 3:
+				// Small moves mostly stay in the same node.
+				box := randomBox(r)
+				if b, ok := items[id]; ok && r.Intn(2) == 0 {
+					offset := randomPoint(r, -0.5, 0.5)
+					box = vec3.Box{Min: vec3.Add(&b.Min, &offset), Max: vec3.Add(&b.Max, &offset)}
+				}
+				if tree.Move(id, &box) != exists {
+					t.Fatalf("Move(%d) = %v, want %v", id, !exists, exists)
+				}
+				if exists {
+					items[id] = box
+				}
+			}
+		}
+		check(t, tree, items, r)
+	}
+
+	for id := range items {
+		tree.Remove(id)
+	}
+	check(t, tree, map[int]vec3.Box{}, r)
+	if tree.root.count != 0 || tree.root.children != [8]*node{} {
+		t.Errorf("the root of the empty tree has %d items and the children %v", tree.root.count, tree.root.children)
+	}
+}
@@ -0,0 +1,299 @@
+// The package quadtree contains a float32 loose quadtree of vec2.Rect items.
+//
+// Every node of a loose quadtree has bounds that are twice the size of its cell,
+// so an item is stored in the deepest node whose cell contains the center
+// of the item and whose cell size is at least the size of the item.
+// This makes inserting, removing and moving items cheap
+// which suits scenes with many moving objects.
+//
+// Items are identified by integer IDs chosen by the user.
+// A T is not safe for concurrent use.
+package quadtree
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/ungerik/go3d/vec2"
+)
+
+// T is a loose quadtree.
+type T struct {
+	root     *node
+	maxDepth int
+	entries  map[int]*entry
+}
+
+type node struct {
+	parent   *node
+	quadrant int
+	center   vec2.T
+	halfSize float32
+	children [4]*node
+	entries  []*entry
+	// count is the number of entries in the subtree.
+	count int
+}
+
+type entry struct {
+	id   int
+	rect vec2.Rect
+	node *node
+}
+
+// New returns an empty quadtree covering bounds that subdivides up to maxDepth levels.
+// Items outside of bounds can be inserted, but are kept in the root node.
+func New(bounds *vec2.Rect, maxDepth int) *T {
+	halfSize := bounds.Width()
+	if bounds.Height() > halfSize {
+		halfSize = bounds.Height()
+	}
+	return &T{
+		root:     &node{center: bounds.Center(), halfSize: halfSize * 0.5},
+		maxDepth: maxDepth,
+		entries:  make(map[int]*entry),
+	}
+}
+
+// String returns a description of the quadtree.
+func (self *T) String() string {
+	return fmt.Sprintf("quadtree with %d items", len(self.entries))
+}
+
+// Len returns the number of items.
+func (self *T) Len() int {
+	return len(self.entries)
+}
+
+// Rect returns the rectangle of the item with id.
+func (self *T) Rect(id int) (rect vec2.Rect, ok bool) {
+	e, ok := self.entries[id]
+	if !ok {
+		return vec2.Rect{}, false
+	}
+	return e.rect, true
+}
+
+// Insert adds an item with id and rect.
+// An existing item with the same id is moved to rect.
+func (self *T) Insert(id int, rect *vec2.Rect) {
+	if self.Move(id, rect) {
+		return
+	}
+	e := &entry{id: id, rect: *rect}
+	self.entries[id] = e
+	self.add(e, self.locate(rect))
+}
+
+// Remove removes the item with id and returns if it existed.
+func (self *T) Remove(id int) bool {
+	e, ok := self.entries[id]
+	if !ok {
+		return false
+	}
+	delete(self.entries, id)
+	self.remove(e, e.node)
+	return true
+}
+
+// Move changes the rectangle of the item with id and returns if it existed.
+func (self *T) Move(id int, rect *vec2.Rect) bool {
+	e, ok := self.entries[id]
+	if !ok {
+		return false
+	}
+	e.rect = *rect
+	if n := self.locate(rect); n != e.node {
+		// Add before removing so that the new nodes
+		// created by locate are not released as empty.
+		old := e.node
+		self.add(e, n)
+		self.remove(e, old)
+	}
+	return true
+}
+
+// QueryRect appends the IDs of all items whose rectangles
+// overlap or touch rect to result and returns it.
+func (self *T) QueryRect(rect *vec2.Rect, result []int) []int {
+	return self.root.query(func(r *vec2.Rect) bool { return r.Intersects(rect) }, true, result)
+}
+
+// QueryRadius appends the IDs of all items whose rectangles
+// are within radius of center to result and returns it.
+func (self *T) QueryRadius(center *vec2.T, radius float32, result []int) []int {
+	radiusSqr := radius * radius
+	return self.root.query(func(r *vec2.Rect) bool { return distanceSqr(r, center) <= radiusSqr }, true, result)
+}
+
+// Nearest appends the IDs of the k items whose rectangles are nearest
+// to point to result, ordered by increasing distance, and returns it.
+func (self *T) Nearest(point *vec2.T, k int, result []int) []int {
+	if k <= 0 || len(self.entries) == 0 {
+		return result
+	}
+	queue := priorityQueue{{node: self.root}}
+	found := 0
+	for len(queue) > 0 && found < k {
+		c := heap.Pop(&queue).(candidate)
+		if c.entry != nil {
+			result = append(result, c.entry.id)
+			found++
+			continue
+		}
+		for _, e := range c.node.entries {
+			heap.Push(&queue, candidate{distanceSqr: distanceSqr(&e.rect, point), entry: e})
+		}
+		for _, child := range c.node.children {
+			if child != nil {
+				bounds := child.looseBounds()
+				heap.Push(&queue, candidate{distanceSqr: distanceSqr(&bounds, point), node: child})
+			}
+		}
+	}
+	return result
+}
+
+// locate returns the node where rect belongs, creating missing nodes on the way.
+func (self *T) locate(rect *vec2.Rect) *node {
+	n := self.root
+	center := rect.Center()
+	maxExtent := rect.Width() * 0.5
+	if h := rect.Height() * 0.5; h > maxExtent {
+		maxExtent = h
+	}
+	if !n.cellContains(&center) {
+		return n
+	}
+	for depth := 0; depth < self.maxDepth; depth++ {
+		childHalfSize := n.halfSize * 0.5
+		// The loose bounds of a child reach childHalfSize beyond its cell.
+		if maxExtent > childHalfSize {
+			break
+		}
+		quadrant := 0
+		for i := 0; i < 2; i++ {
+			if center[i] >= n.center[i] {
+				quadrant |= 1 << uint(i)
+			}
+		}
+		child := n.children[quadrant]
+		if child == nil {
+			child = &node{parent: n, quadrant: quadrant, center: n.center, halfSize: childHalfSize}
+			for i := 0; i < 2; i++ {
+				if quadrant&(1<<uint(i)) != 0 {
+					child.center[i] += childHalfSize
+				} else {
+					child.center[i] -= childHalfSize
+				}
+			}
+			n.children[quadrant] = child
+		}
+		n = child
+	}
+	return n
+}
+
+func (self *T) add(e *entry, n *node) {
+	e.node = n
+	n.entries = append(n.entries, e)
+	for ; n != nil; n = n.parent {
+		n.count++
+	}
+}
+
+func (self *T) remove(e *entry, n *node) {
+	for i, other := range n.entries {
+		if other == e {
+			last := len(n.entries) - 1
+			n.entries[i] = n.entries[last]
+			n.entries[last] = nil
+			n.entries = n.entries[:last]
+			break
+		}
+	}
+	// Decrement the counts up to the root and release empty subtrees.
+	for ; n != nil; n = n.parent {
+		n.count--
+		if n.count == 0 && n.parent != nil {
+			n.parent.children[n.quadrant] = nil
+		}
+	}
+}
+
+func (self *node) cellContains(p *vec2.T) bool {
+	for i := 0; i < 2; i++ {
+		if p[i] < self.center[i]-self.halfSize || p[i] > self.center[i]+self.halfSize {
+			return false
+		}
+	}
+	return true
+}
+
+func (self *node) looseBounds() vec2.Rect {
+	s := 2 * self.halfSize
+	return vec2.Rect{
+		Min: vec2.T{self.center[0] - s, self.center[1] - s},
+		Max: vec2.T{self.center[0] + s, self.center[1] + s},
+	}
+}
+
+// query appends the entries matching test. The root is never culled
+// because it may contain items outside of its loose bounds.
+func (self *node) query(test func(r *vec2.Rect) bool, isRoot bool, result []int) []int {
+	if !isRoot {
+		bounds := self.looseBounds()
+		if !test(&bounds) {
+			return result
+		}
+	}
+	for _, e := range self.entries {
+		if test(&e.rect) {
+			result = append(result, e.id)
+		}
+	}
+	for _, child := range self.children {
+		if child != nil {
+			result = child.query(test, false, result)
+		}
+	}
+	return result
+}
+
+func distanceSqr(rect *vec2.Rect, p *vec2.T) float32 {
+	c := rect.Clamp(p)
+	d := vec2.Sub(&c, p)
+	return d.LengthSqr()
+}
+
+type candidate struct {
+	distanceSqr float32
+	node        *node
+	entry       *entry
+}
+
+// priorityQueue implements heap.Interface with the nearest candidate first.
+type priorityQueue []candidate
+
+func (self priorityQueue) Len() int {
+	return len(self)
+}
+
+func (self priorityQueue) Less(i, j int) bool {
+	return self[i].distanceSqr < self[j].distanceSqr
+}
+
+func (self priorityQueue) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+func (self *priorityQueue) Push(x interface{}) {
+	*self = append(*self, x.(candidate))
+}
+
+func (self *priorityQueue) Pop() interface{} {
+	old := *self
+	c := old[len(old)-1]
+	*self = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,162 @@
+package quadtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ungerik/go3d/vec2"
+)
+
+func random(r *rand.Rand, min, max float32) float32 {
+	return min + float32(r.Float64())*(max-min)
+}
+
+func randomPoint(r *rand.Rand, min, max float32) vec2.T {
+	return vec2.T{random(r, min, max), random(r, min, max)}
+}
+
+// randomRect returns rectangles of very different sizes,
+// some of them outside of the bounds -10..10 of the tests.
+func randomRect(r *rand.Rand) vec2.Rect {
+	center := randomPoint(r, -15, 15)
+	extents := randomPoint(r, 0, 0.1)
+	if r.Intn(4) == 0 {
+		extents = randomPoint(r, 0, 8)
+	}
+	return vec2.Rect{Min: vec2.Sub(&center, &extents), Max: vec2.Add(&center, &extents)}
+}
+
+func sortedIDs(ids []int) []int {
+	sort.Ints(ids)
+	return ids
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// check compares the queries of tree with a linear scan of items.
+func check(t *testing.T, tree *T, items map[int]vec2.Rect, r *rand.Rand) {
+	if tree.Len() != len(items) {
+		t.Fatalf("Len() = %d, want %d", tree.Len(), len(items))
+	}
+	for id, want := range items {
+		if rect, ok := tree.Rect(id); !ok || rect != want {
+			t.Fatalf("Rect(%d) = %v, %v, want %v", id, rect, ok, want)
+		}
+	}
+
+	var result []int
+	for q := 0; q < 20; q++ {
+		rect := randomRect(r)
+		var want []int
+		for id := range items {
+			b := items[id]
+			if b.Intersects(&rect) {
+				want = append(want, id)
+			}
+		}
+		result = tree.QueryRect(&rect, result[:0])
+		if !equalIDs(sortedIDs(result), sortedIDs(want)) {
+			t.Fatalf("QueryRect(%v) = %v, want %v", rect, result, want)
+		}
+
+		center := randomPoint(r, -15, 15)
+		radius := random(r, 0, 5)
+		want = want[:0]
+		for id := range items {
+			b := items[id]
+			if distanceSqr(&b, &center) <= radius*radius {
+				want = append(want, id)
+			}
+		}
+		result = tree.QueryRadius(&center, radius, result[:0])
+		if !equalIDs(sortedIDs(result), sortedIDs(want)) {
+			t.Fatalf("QueryRadius(%v, %f) = %v, want %v", center, radius, result, want)
+		}
+
+		distances := make([]float32, 0, len(items))
+		for id := range items {
+			b := items[id]
+			distances = append(distances, distanceSqr(&b, &center))
+		}
+		sort.Slice(distances, func(i, j int) bool { return distances[i] < distances[j] })
+		for _, k := range []int{0, 1, 5, len(items), len(items) + 3} {
+			result = tree.Nearest(&center, k, result[:0])
+			wantLen := k
+			if wantLen > len(items) {
+				wantLen = len(items)
+			}
+			if len(result) != wantLen {
+				t.Fatalf("Nearest(%v, %d) returned %d items, want %d", center, k, len(result), wantLen)
+			}
+			seen := make(map[int]bool)
+			for i, id := range result {
+				b, ok := items[id]
+				if !ok || seen[id] {
+					t.Fatalf("Nearest(%v, %d) returned the unknown or duplicate id %d", center, k, id)
+				}
+				seen[id] = true
+				if d := distanceSqr(&b, &center); d != distances[i] {
+					t.Fatalf("Nearest(%v, %d)[%d] has distance %f, want %f", center, k, i, d, distances[i])
+				}
+			}
+		}
+	}
+}
+
+func TestRandomOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := New(&vec2.Rect{Min: vec2.T{-10, -10}, Max: vec2.T{10, 10}}, 6)
+	items := make(map[int]vec2.Rect)
+	check(t, tree, items, r)
+	for round := 0; round < 50; round++ {
+		for n := 0; n < 40; n++ {
+			id := r.Intn(200)
+			_, exists := items[id]
+			switch r.Intn(4) {
+			case 0, 1:
+				// Inserting an existing id moves the item.
+				rect := randomRect(r)
+				tree.Insert(id, &rect)
+				items[id] = rect
+			case 2:
+				if tree.Remove(id) != exists {
+					t.Fatalf("Remove(%d) = %v, want %v", id, !exists, exists)
+				}
+				delete(items, id)
+			case 3:
+				// Small moves mostly stay in the same node.
+				rect := randomRect(r)
+				if b, ok := items[id]; ok && r.Intn(2) == 0 {
+					offset := randomPoint(r, -0.5, 0.5)
+					rect = vec2.Rect{Min: vec2.Add(&b.Min, &offset), Max: vec2.Add(&b.Max, &offset)}
+				}
+				if tree.Move(id, &rect) != exists {
+					t.Fatalf("Move(%d) = %v, want %v", id, !exists, exists)
+				}
+				if exists {
+					items[id] = rect
+				}
+			}
+		}
+		check(t, tree, items, r)
+	}
+
+	for id := range items {
+		tree.Remove(id)
+	}
+	check(t, tree, map[int]vec2.Rect{}, r)
+	if tree.root.count != 0 || tree.root.children != [4]*node{} {
+		t.Errorf("the root of the empty tree has %d items and the children %v", tree.root.count, tree.root.children)
+	}
+}
@@ -4,6 +4,12 @@ import (
 	"fmt"
 )
 
+var (
+	// EmptyRect is an inverted rectangle with Min set to MaxVal and Max set to MinVal.
+	// It contains no points and is the identity for Join and ExtendByPoint.
+	EmptyRect = Rect{MaxVal, MinVal}
+)
+
 type Rect struct {
 	Min T
 	Max T
@@ -20,23 +26,213 @@ func (self *Rect) String() string {
 	return self.Min.String() + " " + self.Max.String()
 }
 
+// IsEmpty returns true if the rectangle is inverted in any dimension,
+// like EmptyRect or the result of Intersect for disjoint rectangles.
+func (self *Rect) IsEmpty() bool {
+	return self.Min[0] > self.Max[0] || self.Min[1] > self.Max[1]
+}
+
+// Width returns the extent of the rectangle along the X axis.
+func (self *Rect) Width() float32 {
+	return self.Max[0] - self.Min[0]
+}
+
+// Height returns the extent of the rectangle along the Y axis.
+func (self *Rect) Height() float32 {
+	return self.Max[1] - self.Min[1]
+}
+
+// Size returns the width and height of the rectangle.
+func (self *Rect) Size() T {
+	return Sub(&self.Max, &self.Min)
+}
+
+// Area returns the area of the rectangle or zero if the rectangle is empty.
+func (self *Rect) Area() float32 {
+	if self.IsEmpty() {
+		return 0
+	}
+	return self.Width() * self.Height()
+}
+
+// Center returns the center point of the rectangle.
+func (self *Rect) Center() T {
+	return T{(self.Min[0] + self.Max[0]) * 0.5, (self.Min[1] + self.Max[1]) * 0.5}
+}
+
 func (self *Rect) ContainsPoint(p *T) bool {
 	return p[0] >= self.Min[0] && p[0] <= self.Max[0] &&
 		p[1] >= self.Min[1] && p[1] <= self.Max[1]
 }
 
+// Contains returns true if other lies completely inside of self.
 func (self *Rect) Contains(other *Rect) bool {
-	panic("not implemented")
+	return other.Min[0] >= self.Min[0] && other.Max[0] <= self.Max[0] &&
+		other.Min[1] >= self.Min[1] && other.Max[1] <= self.Max[1]
 }
 
+// Intersects returns true if self and other overlap or touch.
 func (self *Rect) Intersects(other *Rect) bool {
-	panic("not implemented")
+	return self.Min[0] <= other.Max[0] && self.Max[0] >= other.Min[0] &&
+		self.Min[1] <= other.Max[1] && self.Max[1] >= other.Min[1]
+}
+
+// ExtendByPoint grows the rectangle so that it contains p and returns self.
+func (self *Rect) ExtendByPoint(p *T) *Rect {
+	self.Min = Min(&self.Min, p)
+	self.Max = Max(&self.Max, p)
+	return self
+}
+
+// Inset moves all edges of the rectangle inwards by d and returns self.
+// See also Outset.
+func (self *Rect) Inset(d float32) *Rect {
+	self.Min[0] += d
+	self.Min[1] += d
+	self.Max[0] -= d
+	self.Max[1] -= d
+	return self
+}
+
+// Outset moves all edges of the rectangle outwards by d and returns self.
+// See also Inset.
+func (self *Rect) Outset(d float32) *Rect {
+	return self.Inset(-d)
 }
 
+// Clamp returns the point inside or on the border of the rectangle
+// that is closest to p.
+func (self *Rect) Clamp(p *T) T {
+	return T{
+		clamp(p[0], self.Min[0], self.Max[0]),
+		clamp(p[1], self.Min[1], self.Max[1]),
+	}
+}
+
+// Quadrants splits the rectangle at its center into four rectangles
+// in the same order as Grid(2, 2).
+func (self *Rect) Quadrants() [4]Rect {
+	c := self.Center()
+	return [4]Rect{
+		{self.Min, c},
+		{T{c[0], self.Min[1]}, T{self.Max[0], c[1]}},
+		{T{self.Min[0], c[1]}, T{c[0], self.Max[1]}},
+		{c, self.Max},
+	}
+}
+
+// Grid splits the rectangle into cols * rows cells of equal size.
+// The cells are returned row by row, starting at Min.
+func (self *Rect) Grid(cols, rows int) []Rect {
+	if cols <= 0 || rows <= 0 {
+		return nil
+	}
+	cellW := self.Width() / float32(cols)
+	cellH := self.Height() / float32(rows)
+	cells := make([]Rect, 0, cols*rows)
+	for y := 0; y < rows; y++ {
+		minY := self.Min[1] + float32(y)*cellH
+		maxY := self.Min[1] + float32(y+1)*cellH
+		if y == rows-1 {
+			maxY = self.Max[1]
+		}
+		for x := 0; x < cols; x++ {
+			minX := self.Min[0] + float32(x)*cellW
+			maxX := self.Min[0] + float32(x+1)*cellW
+			if x == cols-1 {
+				maxX = self.Max[0]
+			}
+			cells = append(cells, Rect{T{minX, minY}, T{maxX, maxY}})
+		}
+	}
+	return cells
+}
+
+// AspectFit returns the largest rectangle with the aspect ratio of self
+// that fits inside of target and is centered in it.
+func (self *Rect) AspectFit(target *Rect) Rect {
+	w, h := self.Width(), self.Height()
+	if w == 0 || h == 0 {
+		return Rect{target.Center(), target.Center()}
+	}
+	s := target.Width() / w
+	if sh := target.Height() / h; sh < s {
+		s = sh
+	}
+	return centeredRect(target.Center(), w*s, h*s)
+}
+
+// AspectFill returns the smallest rectangle with the aspect ratio of self
+// that covers target completely and is centered on it.
+func (self *Rect) AspectFill(target *Rect) Rect {
+	w, h := self.Width(), self.Height()
+	if w == 0 || h == 0 {
+		return Rect{target.Center(), target.Center()}
+	}
+	s := target.Width() / w
+	if sh := target.Height() / h; sh > s {
+		s = sh
+	}
+	return centeredRect(target.Center(), w*s, h*s)
+}
+
+// ToUV maps p from the rectangle to UV coordinates,
+// where Min maps to (0,0) and Max to (1,1). See also FromUV.
+// If the rectangle has zero width or height,
+// the respective UV coordinate is 0.5.
+func (self *Rect) ToUV(p *T) T {
+	uv := T{0.5, 0.5}
+	if w := self.Width(); w != 0 {
+		uv[0] = (p[0] - self.Min[0]) / w
+	}
+	if h := self.Height(); h != 0 {
+		uv[1] = (p[1] - self.Min[1]) / h
+	}
+	return uv
+}
+
+// FromUV maps the UV coordinates uv to a point in the rectangle,
+// where (0,0) maps to Min and (1,1) to Max. See also ToUV.
+func (self *Rect) FromUV(uv *T) T {
+	return T{
+		self.Min[0] + uv[0]*self.Width(),
+		self.Min[1] + uv[1]*self.Height(),
+	}
+}
+
+// MapPoint maps p from the coordinate space of the rectangle from
+// to the coordinate space of the rectangle to.
+// If from has zero width or height, p maps to the center of to
+// along that axis.
+func MapPoint(p *T, from, to *Rect) T {
+	uv := from.ToUV(p)
+	return to.FromUV(&uv)
+}
+
+// Intersect returns the rectangle where a and b overlap.
+// If a and b don't intersect the result IsEmpty.
 func Intersect(a, b *Rect) Rect {
-	panic("not implemented")
+	return Rect{Max(&a.Min, &b.Min), Min(&a.Max, &b.Max)}
 }
 
+// Join returns the smallest rectangle that contains a and b.
 func Join(a, b *Rect) Rect {
-	panic("not implemented")
+	return Rect{Min(&a.Min, &b.Min), Max(&a.Max, &b.Max)}
+}
+
+func centeredRect(center T, width, height float32) Rect {
+	return Rect{
+		T{center[0] - width*0.5, center[1] - height*0.5},
+		T{center[0] + width*0.5, center[1] + height*0.5},
+	}
+}
+
+func clamp(x, min, max float32) float32 {
+	if x < min {
+		return min
+	}
+	if x > max {
+		return max
+	}
+	return x
 }
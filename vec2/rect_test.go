@@ -0,0 +1,49 @@
+package vec2
+
+import (
+	"testing"
+)
+
+func TestUV(t *testing.T) {
+	r := Rect{T{1, 2}, T{5, 4}}
+	tests := []struct {
+		p, uv T
+	}{
+		{T{1, 2}, T{0, 0}},
+		{T{5, 4}, T{1, 1}},
+		{T{3, 3}, T{0.5, 0.5}},
+		{T{7, 1}, T{1.5, -0.5}},
+	}
+	for _, test := range tests {
+		if uv := r.ToUV(&test.p); uv != test.uv {
+			t.Errorf("ToUV(%v) = %v, want %v", test.p, uv, test.uv)
+		}
+		if p := r.FromUV(&test.uv); p != test.p {
+			t.Errorf("FromUV(%v) = %v, want %v", test.uv, p, test.p)
+		}
+	}
+}
+
+func TestUVZeroSize(t *testing.T) {
+	tests := []struct {
+		r     Rect
+		p, uv T
+	}{
+		{Rect{T{1, 2}, T{1, 4}}, T{3, 3}, T{0.5, 0.5}},
+		{Rect{T{1, 2}, T{5, 2}}, T{3, 3}, T{0.5, 0.5}},
+		{Rect{T{1, 2}, T{5, 2}}, T{2, 7}, T{0.25, 0.5}},
+		{Rect{T{1, 2}, T{1, 2}}, T{3, 3}, T{0.5, 0.5}},
+	}
+	for _, test := range tests {
+		if uv := test.r.ToUV(&test.p); uv != test.uv {
+			t.Errorf("ToUV(%v) of %v = %v, want %v", test.p, test.r, uv, test.uv)
+		}
+	}
+
+	from := Rect{T{1, 2}, T{1, 2}}
+	to := Rect{T{0, 0}, T{10, 20}}
+	p := T{1, 2}
+	if q := MapPoint(&p, &from, &to); q != (T{5, 10}) {
+		t.Errorf("MapPoint from a point rectangle = %v, want the center %v", q, T{5, 10})
+	}
+}
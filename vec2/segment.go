@@ -0,0 +1,192 @@
+package vec2
+
+import (
+	"fmt"
+)
+
+// SegmentIntersection is the kind of intersection of two segments.
+type SegmentIntersection int
+
+const (
+	// SegmentsDisjoint means the segments don't intersect.
+	SegmentsDisjoint SegmentIntersection = iota
+	// SegmentsCross means the segments intersect in a single point.
+	SegmentsCross
+	// SegmentsOverlap means the segments are collinear and share a segment
+	// of non zero length.
+	SegmentsOverlap
+)
+
+// segmentEpsilon is the relative tolerance for parallel and collinear segments.
+const segmentEpsilon = 1e-6 //gend:float64 1e-12
+
+// Segment is the line segment from A to B.
+// The parameter t of the methods is 0 at A and 1 at B.
+type Segment struct {
+	A T
+	B T
+}
+
+// ParseSegment parses a Segment from a string. See also String()
+func ParseSegment(s string) (r Segment, err error) {
+	_, err = fmt.Sscanf(s, "%f %f %f %f", &r.A[0], &r.A[1], &r.B[0], &r.B[1])
+	return r, err
+}
+
+// String formats Segment as string. See also ParseSegment().
+func (self *Segment) String() string {
+	return self.A.String() + " " + self.B.String()
+}
+
+// Length returns the length of the segment.
+func (self *Segment) Length() float32 {
+	d := Sub(&self.B, &self.A)
+	return d.Length()
+}
+
+// PointAt returns the point A + (B - A) * t.
+func (self *Segment) PointAt(t float32) T {
+	return T{
+		self.A[0] + (self.B[0]-self.A[0])*t,
+		self.A[1] + (self.B[1]-self.A[1])*t,
+	}
+}
+
+// Bounds returns the axis aligned bounding rectangle of the segment.
+func (self *Segment) Bounds() Rect {
+	return Rect{Min(&self.A, &self.B), Max(&self.A, &self.B)}
+}
+
+// ClosestPoint returns the point on the segment that is closest to p
+// and its parameter t.
+func (self *Segment) ClosestPoint(p *T) (T, float32) {
+	ab := Sub(&self.B, &self.A)
+	lengthSqr := ab.LengthSqr()
+	if lengthSqr == 0 {
+		return self.A, 0
+	}
+	ap := Sub(p, &self.A)
+	t := clamp(Dot(&ap, &ab)/lengthSqr, 0, 1)
+	return self.PointAt(t), t
+}
+
+// DistanceToPoint returns the distance from p to the segment.
+func (self *Segment) DistanceToPoint(p *T) float32 {
+	c, _ := self.ClosestPoint(p)
+	d := Sub(p, &c)
+	return d.Length()
+}
+
+// DistanceToPointSqr returns the squared distance from p to the segment.
+func (self *Segment) DistanceToPointSqr(p *T) float32 {
+	c, _ := self.ClosestPoint(p)
+	d := Sub(p, &c)
+	return d.LengthSqr()
+}
+
+// ClosestPoints returns the points p on self and q on other that are
+// closest to each other together with their parameters s and t.
+// For parallel segments one of the closest pairs is returned.
+func (self *Segment) ClosestPoints(other *Segment) (s, t float32, p, q T) {
+	d1 := Sub(&self.B, &self.A)
+	d2 := Sub(&other.B, &other.A)
+	r := Sub(&self.A, &other.A)
+	a := d1.LengthSqr()
+	e := d2.LengthSqr()
+	f := Dot(&d2, &r)
+
+	switch {
+	case a == 0 && e == 0:
+		return 0, 0, self.A, other.A
+	case a == 0:
+		t = clamp(f/e, 0, 1)
+	default:
+		c := Dot(&d1, &r)
+		if e == 0 {
+			s = clamp(-c/a, 0, 1)
+		} else {
+			b := Dot(&d1, &d2)
+			denom := a*e - b*b
+			if denom != 0 {
+				s = clamp((b*f-c*e)/denom, 0, 1)
+			}
+			t = (b*s + f) / e
+			if t < 0 {
+				t = 0
+				s = clamp(-c/a, 0, 1)
+			} else if t > 1 {
+				t = 1
+				s = clamp((b-c)/a, 0, 1)
+			}
+		}
+	}
+	return s, t, self.PointAt(s), other.PointAt(t)
+}
+
+// DistanceToSegment returns the smallest distance between self and other.
+func (self *Segment) DistanceToSegment(other *Segment) float32 {
+	_, _, p, q := self.ClosestPoints(other)
+	d := Sub(&p, &q)
+	return d.Length()
+}
+
+// Intersect intersects self with other.
+// For SegmentsCross the returned segment has the intersection point as A and B,
+// for SegmentsOverlap it is the shared part in the direction of self.
+func (self *Segment) Intersect(other *Segment) (SegmentIntersection, Segment) {
+	r := Sub(&self.B, &self.A)
+	s := Sub(&other.B, &other.A)
+	ac := Sub(&other.A, &self.A)
+	rLengthSqr := r.LengthSqr()
+	sLengthSqr := s.LengthSqr()
+
+	if rLengthSqr == 0 {
+		if other.DistanceToPointSqr(&self.A) <= segmentEpsilon*segmentEpsilon*sLengthSqr {
+			return SegmentsCross, Segment{self.A, self.A}
+		}
+		return SegmentsDisjoint, Segment{}
+	}
+
+	denom := perpDot(&r, &s)
+	if denom*denom <= segmentEpsilon*segmentEpsilon*rLengthSqr*sLengthSqr {
+		// parallel
+		if d := perpDot(&ac, &r); d*d > segmentEpsilon*segmentEpsilon*rLengthSqr*ac.LengthSqr() {
+			return SegmentsDisjoint, Segment{}
+		}
+		// collinear, so intersect the parameter ranges on self
+		t0 := Dot(&ac, &r) / rLengthSqr
+		t1 := t0 + Dot(&s, &r)/rLengthSqr
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		if t0 < 0 {
+			t0 = 0
+		}
+		if t1 > 1 {
+			t1 = 1
+		}
+		switch {
+		case t0 > t1:
+			return SegmentsDisjoint, Segment{}
+		case t0 == t1:
+			p := self.PointAt(t0)
+			return SegmentsCross, Segment{p, p}
+		default:
+			return SegmentsOverlap, Segment{self.PointAt(t0), self.PointAt(t1)}
+		}
+	}
+
+	t := perpDot(&ac, &s) / denom
+	u := perpDot(&ac, &r) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return SegmentsDisjoint, Segment{}
+	}
+	p := self.PointAt(t)
+	return SegmentsCross, Segment{p, p}
+}
+
+// Intersects returns true if self and other have at least one point in common.
+func (self *Segment) Intersects(other *Segment) bool {
+	kind, _ := self.Intersect(other)
+	return kind != SegmentsDisjoint
+}
@@ -0,0 +1,202 @@
+package vec2
+
+import (
+	"fmt"
+)
+
+// Triangle is the triangle with the corners A, B and C.
+type Triangle struct {
+	A T
+	B T
+	C T
+}
+
+// ParseTriangle parses a Triangle from a string. See also String()
+func ParseTriangle(s string) (r Triangle, err error) {
+	_, err = fmt.Sscanf(s, "%f %f %f %f %f %f",
+		&r.A[0], &r.A[1],
+		&r.B[0], &r.B[1],
+		&r.C[0], &r.C[1],
+	)
+	return r, err
+}
+
+// String formats Triangle as string. See also ParseTriangle().
+func (self *Triangle) String() string {
+	return self.A.String() + " " + self.B.String() + " " + self.C.String()
+}
+
+// SignedArea returns the area of the triangle,
+// which is positive if A, B, C are counter clockwise and negative otherwise.
+func (self *Triangle) SignedArea() float32 {
+	ab := Sub(&self.B, &self.A)
+	ac := Sub(&self.C, &self.A)
+	return perpDot(&ab, &ac) * 0.5
+}
+
+// Area returns the area of the triangle.
+func (self *Triangle) Area() float32 {
+	a := self.SignedArea()
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Centroid returns the center of mass of the triangle.
+func (self *Triangle) Centroid() T {
+	return T{
+		(self.A[0] + self.B[0] + self.C[0]) / 3,
+		(self.A[1] + self.B[1] + self.C[1]) / 3,
+	}
+}
+
+// Bounds returns the axis aligned bounding rectangle of the triangle.
+func (self *Triangle) Bounds() Rect {
+	rect := Rect{self.A, self.A}
+	rect.ExtendByPoint(&self.B)
+	rect.ExtendByPoint(&self.C)
+	return rect
+}
+
+// Barycentric returns the barycentric coordinates u, v, w of p,
+// so that PointFromBarycentric(u, v, w) equals p.
+// The triangle must not be degenerate.
+func (self *Triangle) Barycentric(p *T) (u, v, w float32) {
+	ab := Sub(&self.B, &self.A)
+	ac := Sub(&self.C, &self.A)
+	ap := Sub(p, &self.A)
+	ooDenom := 1 / perpDot(&ab, &ac)
+	v = perpDot(&ap, &ac) * ooDenom
+	w = perpDot(&ab, &ap) * ooDenom
+	return 1 - v - w, v, w
+}
+
+// PointFromBarycentric returns the point A*u + B*v + C*w.
+func (self *Triangle) PointFromBarycentric(u, v, w float32) T {
+	return T{
+		self.A[0]*u + self.B[0]*v + self.C[0]*w,
+		self.A[1]*u + self.B[1]*v + self.C[1]*w,
+	}
+}
+
+// ContainsPoint returns true if p lies inside of the triangle or on its border.
+func (self *Triangle) ContainsPoint(p *T) bool {
+	u, v, w := self.Barycentric(p)
+	return u >= 0 && v >= 0 && w >= 0
+}
+
+// ClosestPoint returns the point on the triangle that is closest to p.
+// Points inside of the triangle are returned unchanged.
+func (self *Triangle) ClosestPoint(p *T) T {
+	a, b, c := &self.A, &self.B, &self.C
+	ab := Sub(b, a)
+	ac := Sub(c, a)
+	ap := Sub(p, a)
+	d1 := Dot(&ab, &ap)
+	d2 := Dot(&ac, &ap)
+	if d1 <= 0 && d2 <= 0 {
+		return *a
+	}
+
+	bp := Sub(p, b)
+	d3 := Dot(&ab, &bp)
+	d4 := Dot(&ac, &bp)
+	if d3 >= 0 && d4 <= d3 {
+		return *b
+	}
+
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return self.PointFromBarycentric(1-d1/(d1-d3), d1/(d1-d3), 0)
+	}
+
+	cp := Sub(p, c)
+	d5 := Dot(&ab, &cp)
+	d6 := Dot(&ac, &cp)
+	if d6 >= 0 && d5 <= d6 {
+		return *c
+	}
+
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return self.PointFromBarycentric(1-d2/(d2-d6), 0, d2/(d2-d6))
+	}
+
+	va := d3*d6 - d5*d4
+	if va <= 0 && (d4-d3) >= 0 && (d5-d6) >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return self.PointFromBarycentric(0, 1-w, w)
+	}
+
+	return *p
+}
+
+// Intersects returns true if self and other overlap or touch.
+func (self *Triangle) Intersects(other *Triangle) bool {
+	for _, t := range [2]*Triangle{self, other} {
+		edges := t.edges()
+		for i := range edges {
+			axis := T{-edges[i][1], edges[i][0]}
+			min, max := self.project(&axis)
+			otherMin, otherMax := other.project(&axis)
+			if min > otherMax || max < otherMin {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IntersectsRect returns true if the triangle and rect overlap or touch.
+func (self *Triangle) IntersectsRect(rect *Rect) bool {
+	bounds := self.Bounds()
+	if !bounds.Intersects(rect) {
+		return false
+	}
+	corners := [4]T{rect.Min, {rect.Max[0], rect.Min[1]}, {rect.Min[0], rect.Max[1]}, rect.Max}
+	edges := self.edges()
+	for i := range edges {
+		axis := T{-edges[i][1], edges[i][0]}
+		min, max := self.project(&axis)
+		rectMin, rectMax := Dot(&corners[0], &axis), Dot(&corners[0], &axis)
+		for j := 1; j < 4; j++ {
+			d := Dot(&corners[j], &axis)
+			if d < rectMin {
+				rectMin = d
+			} else if d > rectMax {
+				rectMax = d
+			}
+		}
+		if min > rectMax || max < rectMin {
+			return false
+		}
+	}
+	return true
+}
+
+func (self *Triangle) edges() [3]T {
+	return [3]T{
+		Sub(&self.B, &self.A),
+		Sub(&self.C, &self.B),
+		Sub(&self.A, &self.C),
+	}
+}
+
+func (self *Triangle) project(axis *T) (min, max float32) {
+	min = Dot(&self.A, axis)
+	max = min
+	for _, d := range [2]float32{Dot(&self.B, axis), Dot(&self.C, axis)} {
+		if d < min {
+			min = d
+		} else if d > max {
+			max = d
+		}
+	}
+	return min, max
+}
+
+// perpDot returns the Z component of the 3D cross product of a and b.
+func perpDot(a, b *T) float32 {
+	return a[0]*b[1] - a[1]*b[0]
+}
@@ -61,6 +61,11 @@ func (self *T) Get(col, row int) float32 {
 	return self[row]
 }
 
+// Set sets one element of the vector.
+func (self *T) Set(col, row int, value float32) {
+	self[row] = value
+}
+
 // IsZero checks if all elements of the vector are zero.
 func (self *T) IsZero() bool {
 	return self[0] == 0 && self[1] == 0
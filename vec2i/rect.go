@@ -0,0 +1,127 @@
+package vec2i
+
+import (
+	"fmt"
+
+	"github.com/ungerik/go3d/vec2"
+)
+
+// Rect is a rectangle of grid cells from Min including to Max excluding.
+type Rect struct {
+	Min T
+	Max T
+}
+
+// RectCovering returns the smallest Rect containing all cells overlapped by rect.
+func RectCovering(rect *vec2.Rect) Rect {
+	max := Floor(&rect.Max)
+	return Rect{Floor(&rect.Min), T{max[0] + 1, max[1] + 1}}
+}
+
+// ParseRect parses a Rect from a string. See also String()
+func ParseRect(s string) (r Rect, err error) {
+	_, err = fmt.Sscanf(s, "%d %d %d %d", &r.Min[0], &r.Min[1], &r.Max[0], &r.Max[1])
+	return r, err
+}
+
+// String formats Rect as string. See also ParseRect().
+func (self *Rect) String() string {
+	return self.Min.String() + " " + self.Max.String()
+}
+
+// IsEmpty returns true if the rectangle contains no cells.
+func (self *Rect) IsEmpty() bool {
+	return self.Max[0] <= self.Min[0] || self.Max[1] <= self.Min[1]
+}
+
+func (self *Rect) Width() int32 {
+	if self.IsEmpty() {
+		return 0
+	}
+	return self.Max[0] - self.Min[0]
+}
+
+func (self *Rect) Height() int32 {
+	if self.IsEmpty() {
+		return 0
+	}
+	return self.Max[1] - self.Min[1]
+}
+
+// Size returns the number of cells along both axes.
+func (self *Rect) Size() T {
+	return T{self.Width(), self.Height()}
+}
+
+// Area returns the number of cells in the rectangle.
+func (self *Rect) Area() int {
+	return int(self.Width()) * int(self.Height())
+}
+
+// ContainsPoint returns if the cell p is inside the rectangle.
+func (self *Rect) ContainsPoint(p *T) bool {
+	return p[0] >= self.Min[0] && p[0] < self.Max[0] &&
+		p[1] >= self.Min[1] && p[1] < self.Max[1]
+}
+
+// Contains returns if all cells of other are inside the rectangle.
+func (self *Rect) Contains(other *Rect) bool {
+	return other.Min[0] >= self.Min[0] && other.Max[0] <= self.Max[0] &&
+		other.Min[1] >= self.Min[1] && other.Max[1] <= self.Max[1]
+}
+
+// Intersects returns if the rectangles share at least one cell.
+func (self *Rect) Intersects(other *Rect) bool {
+	return self.Min[0] < other.Max[0] && other.Min[0] < self.Max[0] &&
+		self.Min[1] < other.Max[1] && other.Min[1] < self.Max[1]
+}
+
+// ExtendByPoint grows the rectangle to contain the cell p.
+func (self *Rect) ExtendByPoint(p *T) *Rect {
+	if self.IsEmpty() {
+		self.Min = *p
+		self.Max = T{p[0] + 1, p[1] + 1}
+		return self
+	}
+	end := T{p[0] + 1, p[1] + 1}
+	self.Min = Min(&self.Min, p)
+	self.Max = Max(&self.Max, &end)
+	return self
+}
+
+// Vec2Rect returns the space covered by the cells as vec2.Rect.
+func (self *Rect) Vec2Rect() vec2.Rect {
+	return vec2.Rect{Min: self.Min.Vec2(), Max: self.Max.Vec2()}
+}
+
+// ForEachCell calls f for every cell of the rectangle row by row
+// until f returns false.
+func (self *Rect) ForEachCell(f func(cell T) bool) {
+	if self.IsEmpty() {
+		return
+	}
+	var c T
+	for c[1] = self.Min[1]; c[1] < self.Max[1]; c[1]++ {
+		for c[0] = self.Min[0]; c[0] < self.Max[0]; c[0]++ {
+			if !f(c) {
+				return
+			}
+		}
+	}
+}
+
+// Intersect returns the cells shared by a and b.
+func Intersect(a, b *Rect) Rect {
+	return Rect{Max(&a.Min, &b.Min), Min(&a.Max, &b.Max)}
+}
+
+// Join returns the smallest rectangle containing a and b.
+func Join(a, b *Rect) Rect {
+	if a.IsEmpty() {
+		return *b
+	}
+	if b.IsEmpty() {
+		return *a
+	}
+	return Rect{Min(&a.Min, &b.Min), Max(&a.Max, &b.Max)}
+}
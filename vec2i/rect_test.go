@@ -0,0 +1,105 @@
+package vec2i
+
+import (
+	"testing"
+
+	"github.com/ungerik/go3d/vec2"
+)
+
+func TestRectCovering(t *testing.T) {
+	tests := []struct {
+		rect vec2.Rect
+		want Rect
+	}{
+		{vec2.Rect{Min: vec2.T{0.5, 0.5}, Max: vec2.T{0.7, 0.7}}, Rect{T{0, 0}, T{1, 1}}},
+		{vec2.Rect{Min: vec2.T{-0.5, -1.5}, Max: vec2.T{0.5, -0.5}}, Rect{T{-1, -2}, T{1, 0}}},
+		{vec2.Rect{Min: vec2.T{-3.2, -2.5}, Max: vec2.T{-1.9, -2.2}}, Rect{T{-4, -3}, T{-1, -2}}},
+		// Rectangles are closed, so a rectangle ending on a cell border touches the next cell.
+		{vec2.Rect{Min: vec2.T{-1, 0}, Max: vec2.T{1, 2}}, Rect{T{-1, 0}, T{2, 3}}},
+	}
+	for _, test := range tests {
+		r := RectCovering(&test.rect)
+		if r != test.want {
+			t.Errorf("RectCovering(%v) = %v, want %v", test.rect, r, test.want)
+		}
+		for _, corner := range []vec2.T{test.rect.Min, test.rect.Max, {test.rect.Min[0], test.rect.Max[1]}} {
+			if c := Floor(&corner); !r.ContainsPoint(&c) {
+				t.Errorf("RectCovering(%v) = %v does not contain the cell %v", test.rect, r, c)
+			}
+		}
+	}
+}
+
+func TestRectCells(t *testing.T) {
+	r := Rect{T{-1, -2}, T{2, 1}}
+	if a := r.Area(); a != 9 {
+		t.Errorf("Area of %v = %d, want 9", r, a)
+	}
+	if s := r.Size(); s != (T{3, 3}) {
+		t.Errorf("Size of %v = %v, want 3 3", r, s)
+	}
+	for _, p := range []T{r.Min, {0, 0}, {1, -2}} {
+		if !r.ContainsPoint(&p) {
+			t.Errorf("%v does not contain %v", r, p)
+		}
+	}
+	// Max is excluded.
+	for _, p := range []T{r.Max, {2, 0}, {0, 1}, {-2, 0}} {
+		if r.ContainsPoint(&p) {
+			t.Errorf("%v contains %v", r, p)
+		}
+	}
+	empty := Rect{T{1, 1}, T{1, 5}}
+	if !empty.IsEmpty() || empty.Area() != 0 || empty.ContainsPoint(&empty.Min) {
+		t.Errorf("%v is not empty", empty)
+	}
+	inverted := Rect{T{1, 1}, T{0, 5}}
+	if inverted.Area() != 0 {
+		t.Errorf("Area of %v = %d, want 0", inverted, inverted.Area())
+	}
+
+	var cells []T
+	r.ForEachCell(func(cell T) bool {
+		if !r.ContainsPoint(&cell) {
+			t.Errorf("ForEachCell of %v visited %v", r, cell)
+		}
+		cells = append(cells, cell)
+		return true
+	})
+	if len(cells) != r.Area() {
+		t.Fatalf("ForEachCell of %v visited %d cells, want %d", r, len(cells), r.Area())
+	}
+	// Row by row with X varying fastest.
+	for i := 1; i < len(cells); i++ {
+		prev, cell := cells[i-1], cells[i]
+		if cell[1] < prev[1] || cell[1] == prev[1] && cell[0] <= prev[0] {
+			t.Fatalf("ForEachCell of %v visited %v after %v", r, cell, prev)
+		}
+	}
+	if cells[0] != r.Min || cells[1] != (T{0, -2}) || cells[3] != (T{-1, -1}) {
+		t.Errorf("ForEachCell of %v started with %v", r, cells[:4])
+	}
+
+	count := 0
+	r.ForEachCell(func(cell T) bool {
+		count++
+		return count < 5
+	})
+	if count != 5 {
+		t.Errorf("ForEachCell continued after f returned false and visited %d cells", count)
+	}
+	empty.ForEachCell(func(cell T) bool {
+		t.Errorf("ForEachCell of the empty %v visited %v", empty, cell)
+		return true
+	})
+}
+
+func TestParseRect(t *testing.T) {
+	for _, r := range []Rect{{}, {T{-1, -2}, T{4, 5}}, {MinVal, MaxVal}} {
+		s := r.String()
+		p, err := ParseRect(s)
+		if err != nil || p != r {
+			t.Errorf("ParseRect(%q) = %v, %v, want %v", s, p, err, r)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// The package vec2i contains an int32 2D vector type T
+// for grid cells and tile coordinates.
+package vec2i
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ungerik/go3d/vec2"
+)
+
+var (
+	Zero = T{}
+
+	UnitX = T{1, 0}
+	UnitY = T{0, 1}
+
+	MinVal = T{math.MinInt32, math.MinInt32}
+	MaxVal = T{math.MaxInt32, math.MaxInt32}
+)
+
+type T [2]int32
+
+// Floor returns the cell containing v, rounding every element down.
+func Floor(v *vec2.T) T {
+	return T{int32(math.Floor(float64(v[0]))), int32(math.Floor(float64(v[1])))}
+}
+
+// Round returns v with every element rounded to the nearest integer,
+// rounding half away from zero.
+func Round(v *vec2.T) T {
+	return T{int32(math.Round(float64(v[0]))), int32(math.Round(float64(v[1])))}
+}
+
+// Ceil returns v with every element rounded up.
+func Ceil(v *vec2.T) T {
+	return T{int32(math.Ceil(float64(v[0]))), int32(math.Ceil(float64(v[1])))}
+}
+
+// Parse parses T from a string. See also String()
+func Parse(s string) (r T, err error) {
+	_, err = fmt.Sscanf(s, "%d %d", &r[0], &r[1])
+	return r, err
+}
+
+// String formats T as string. See also Parse().
+func (self *T) String() string {
+	return fmt.Sprintf("%d %d", self[0], self[1])
+}
+
+// Rows returns the number of rows of the vector.
+func (self *T) Rows() int {
+	return 2
+}
+
+// Cols returns the number of columns of the vector.
+func (self *T) Cols() int {
+	return 1
+}
+
+// Size returns the number elements of the vector.
+func (self *T) Size() int {
+	return 2
+}
+
+// Slice returns the elements of the vector as slice.
+func (self *T) Slice() []int32 {
+	return []int32{self[0], self[1]}
+}
+
+// Get returns one element of the vector.
+func (self *T) Get(col, row int) int32 {
+	return self[row]
+}
+
+// IsZero checks if all elements of the vector are zero.
+func (self *T) IsZero() bool {
+	return self[0] == 0 && self[1] == 0
+}
+
+// LengthSqr returns the squared length of the vector.
+func (self *T) LengthSqr() int32 {
+	return self[0]*self[0] + self[1]*self[1]
+}
+
+// Vec2 converts the vector to a vec2.T.
+func (self *T) Vec2() vec2.T {
+	return vec2.T{float32(self[0]), float32(self[1])}
+}
+
+// Scale multiplies all element of the vector by f and returns self.
+func (self *T) Scale(f int32) *T {
+	self[0] *= f
+	self[1] *= f
+	return self
+}
+
+// Scaled returns a copy of self with all elements multiplies by f.
+func (self *T) Scaled(f int32) T {
+	return T{self[0] * f, self[1] * f}
+}
+
+func (self *T) Invert() *T {
+	self[0] = -self[0]
+	self[1] = -self[1]
+	return self
+}
+
+func (self *T) Inverted() T {
+	return T{-self[0], -self[1]}
+}
+
+func (self *T) Add(v *T) *T {
+	self[0] += v[0]
+	self[1] += v[1]
+	return self
+}
+
+func (self *T) Sub(v *T) *T {
+	self[0] -= v[0]
+	self[1] -= v[1]
+	return self
+}
+
+func (self *T) Mul(v *T) *T {
+	self[0] *= v[0]
+	self[1] *= v[1]
+	return self
+}
+
+func Add(a, b *T) T {
+	return T{a[0] + b[0], a[1] + b[1]}
+}
+
+func Sub(a, b *T) T {
+	return T{a[0] - b[0], a[1] - b[1]}
+}
+
+func Mul(a, b *T) T {
+	return T{a[0] * b[0], a[1] * b[1]}
+}
+
+func Dot(a, b *T) int32 {
+	return a[0]*b[0] + a[1]*b[1]
+}
+
+func Min(a, b *T) T {
+	min := *a
+	if b[0] < min[0] {
+		min[0] = b[0]
+	}
+	if b[1] < min[1] {
+		min[1] = b[1]
+	}
+	return min
+}
+
+func Max(a, b *T) T {
+	max := *a
+	if b[0] > max[0] {
+		max[0] = b[0]
+	}
+	if b[1] > max[1] {
+		max[1] = b[1]
+	}
+	return max
+}
+
+// ManhattanDistance returns the sum of the absolute element differences of a and b.
+func ManhattanDistance(a, b *T) int32 {
+	return abs(a[0]-b[0]) + abs(a[1]-b[1])
+}
+
+// ChebyshevDistance returns the largest absolute element difference of a and b.
+func ChebyshevDistance(a, b *T) int32 {
+	d := abs(a[0] - b[0])
+	if dy := abs(a[1] - b[1]); dy > d {
+		d = dy
+	}
+	return d
+}
+
+func abs(x int32) int32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
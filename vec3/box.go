@@ -2,6 +2,14 @@ package vec3
 
 import (
 	"fmt"
+
+	"github.com/barnex/fmath"
+)
+
+var (
+	// EmptyBox is an inverted box with Min set to MaxVal and Max set to MinVal.
+	// It contains no points and is the identity for Join, ExtendByPoint and ExtendByBox.
+	EmptyBox = Box{MaxVal, MinVal}
 )
 
 type Box struct {
@@ -20,24 +28,175 @@ func (self *Box) String() string {
 	return self.Min.String() + " " + self.Max.String()
 }
 
+// IsEmpty returns true if the box is inverted in any dimension,
+// like EmptyBox or the result of Intersect for disjoint boxes.
+func (self *Box) IsEmpty() bool {
+	return self.Min[0] > self.Max[0] || self.Min[1] > self.Max[1] || self.Min[2] > self.Max[2]
+}
+
 func (self *Box) ContainsPoint(p *T) bool {
 	return p[0] >= self.Min[0] && p[0] <= self.Max[0] &&
 		p[1] >= self.Min[1] && p[1] <= self.Max[1] &&
 		p[2] >= self.Min[2] && p[2] <= self.Max[2]
 }
 
+// Support returns the corner of the box that is farthest in direction dir.
+func (self *Box) Support(dir *T) T {
+	s := self.Min
+	for i := 0; i < 3; i++ {
+		if dir[i] > 0 {
+			s[i] = self.Max[i]
+		}
+	}
+	return s
+}
+
+// Contains returns true if other lies completely inside of self.
 func (self *Box) Contains(other *Box) bool {
-	panic("not implemented")
+	return other.Min[0] >= self.Min[0] && other.Max[0] <= self.Max[0] &&
+		other.Min[1] >= self.Min[1] && other.Max[1] <= self.Max[1] &&
+		other.Min[2] >= self.Min[2] && other.Max[2] <= self.Max[2]
 }
 
+// Intersects returns true if self and other overlap or touch.
 func (self *Box) Intersects(other *Box) bool {
-	panic("not implemented")
+	return self.Min[0] <= other.Max[0] && self.Max[0] >= other.Min[0] &&
+		self.Min[1] <= other.Max[1] && self.Max[1] >= other.Min[1] &&
+		self.Min[2] <= other.Max[2] && self.Max[2] >= other.Min[2]
+}
+
+// Center returns the center point of the box.
+func (self *Box) Center() T {
+	return T{
+		(self.Min[0] + self.Max[0]) * 0.5,
+		(self.Min[1] + self.Max[1]) * 0.5,
+		(self.Min[2] + self.Max[2]) * 0.5,
+	}
 }
 
+// Size returns the width, height and depth of the box.
+// See also Extents.
+func (self *Box) Size() T {
+	return Sub(&self.Max, &self.Min)
+}
+
+// Extents returns the half size of the box,
+// that is the distance from the center to the faces.
+// See also Size.
+func (self *Box) Extents() T {
+	return T{
+		(self.Max[0] - self.Min[0]) * 0.5,
+		(self.Max[1] - self.Min[1]) * 0.5,
+		(self.Max[2] - self.Min[2]) * 0.5,
+	}
+}
+
+// Volume returns the volume of the box or zero if the box is empty.
+func (self *Box) Volume() float32 {
+	if self.IsEmpty() {
+		return 0
+	}
+	s := self.Size()
+	return s[0] * s[1] * s[2]
+}
+
+// SurfaceArea returns the area of the six faces of the box or zero if the box is empty.
+func (self *Box) SurfaceArea() float32 {
+	if self.IsEmpty() {
+		return 0
+	}
+	s := self.Size()
+	return 2 * (s[0]*s[1] + s[1]*s[2] + s[2]*s[0])
+}
+
+// ExtendByPoint grows the box so that it contains p and returns self.
+func (self *Box) ExtendByPoint(p *T) *Box {
+	self.Min = Min(&self.Min, p)
+	self.Max = Max(&self.Max, p)
+	return self
+}
+
+// ExtendByBox grows the box so that it contains other and returns self.
+func (self *Box) ExtendByBox(other *Box) *Box {
+	self.Min = Min(&self.Min, &other.Min)
+	self.Max = Max(&self.Max, &other.Max)
+	return self
+}
+
+// Expand moves all faces of the box outwards by margin and returns self.
+// A negative margin shrinks the box.
+func (self *Box) Expand(margin float32) *Box {
+	self.Min[0] -= margin
+	self.Min[1] -= margin
+	self.Min[2] -= margin
+	self.Max[0] += margin
+	self.Max[1] += margin
+	self.Max[2] += margin
+	return self
+}
+
+// Expanded returns a copy of the box with all faces moved outwards by margin.
+func (self *Box) Expanded(margin float32) Box {
+	r := *self
+	return *r.Expand(margin)
+}
+
+// ClosestPoint returns the point inside or on the surface of the box
+// that is closest to p.
+func (self *Box) ClosestPoint(p *T) T {
+	return T{
+		clamp(p[0], self.Min[0], self.Max[0]),
+		clamp(p[1], self.Min[1], self.Max[1]),
+		clamp(p[2], self.Min[2], self.Max[2]),
+	}
+}
+
+// DistanceToPoint returns the distance from p to the box.
+// Points inside of the box have a distance of zero.
+func (self *Box) DistanceToPoint(p *T) float32 {
+	return fmath.Sqrt(self.DistanceToPointSqr(p))
+}
+
+// DistanceToPointSqr returns the squared distance from p to the box.
+func (self *Box) DistanceToPointSqr(p *T) float32 {
+	c := self.ClosestPoint(p)
+	d := Sub(p, &c)
+	return d.LengthSqr()
+}
+
+// Corners returns the eight corner points of the box.
+// Bit 0 of the index selects Max over Min for X, bit 1 for Y and bit 2 for Z.
+func (self *Box) Corners() [8]T {
+	var corners [8]T
+	for i := range corners {
+		for j := 0; j < 3; j++ {
+			if i&(1<<uint(j)) != 0 {
+				corners[i][j] = self.Max[j]
+			} else {
+				corners[i][j] = self.Min[j]
+			}
+		}
+	}
+	return corners
+}
+
+// Intersect returns the box where a and b overlap.
+// If a and b don't intersect the result IsEmpty.
 func Intersect(a, b *Box) Box {
-	panic("not implemented")
+	return Box{Max(&a.Min, &b.Min), Min(&a.Max, &b.Max)}
 }
 
+// Join returns the smallest box that contains a and b.
 func Join(a, b *Box) Box {
-	panic("not implemented")
+	return Box{Min(&a.Min, &b.Min), Max(&a.Max, &b.Max)}
+}
+
+func clamp(x, min, max float32) float32 {
+	if x < min {
+		return min
+	}
+	if x > max {
+		return max
+	}
+	return x
 }
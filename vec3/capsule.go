@@ -0,0 +1,57 @@
+package vec3
+
+import (
+	"fmt"
+)
+
+// Capsule consists of all points within Radius distance to Segment.
+type Capsule struct {
+	Segment Segment
+	Radius  float32
+}
+
+// ParseCapsule parses a Capsule from a string. See also String()
+func ParseCapsule(s string) (r Capsule, err error) {
+	_, err = fmt.Sscanf(s, "%f %f %f %f %f %f %f",
+		&r.Segment.A[0], &r.Segment.A[1], &r.Segment.A[2],
+		&r.Segment.B[0], &r.Segment.B[1], &r.Segment.B[2],
+		&r.Radius,
+	)
+	return r, err
+}
+
+// String formats Capsule as string. See also ParseCapsule().
+func (self *Capsule) String() string {
+	return fmt.Sprintf("%s %f", self.Segment.String(), self.Radius)
+}
+
+// Bounds returns the axis aligned bounding box of the capsule.
+func (self *Capsule) Bounds() Box {
+	box := self.Segment.Bounds()
+	return *box.Expand(self.Radius)
+}
+
+// Support returns the point of the capsule that is farthest in direction dir.
+func (self *Capsule) Support(dir *T) T {
+	s := self.Segment.Support(dir)
+	sphere := Sphere{s, self.Radius}
+	return sphere.Support(dir)
+}
+
+func (self *Capsule) ContainsPoint(p *T) bool {
+	return self.Segment.DistanceToPointSqr(p) <= self.Radius*self.Radius
+}
+
+// IntersectsSphere returns true if the capsule and sphere overlap or touch.
+func (self *Capsule) IntersectsSphere(sphere *Sphere) bool {
+	r := self.Radius + sphere.Radius
+	return self.Segment.DistanceToPointSqr(&sphere.Center) <= r*r && !sphere.IsEmpty()
+}
+
+// Intersects returns true if self and other overlap or touch.
+func (self *Capsule) Intersects(other *Capsule) bool {
+	_, _, p, q := self.Segment.ClosestPoints(&other.Segment)
+	d := Sub(&p, &q)
+	r := self.Radius + other.Radius
+	return d.LengthSqr() <= r*r
+}
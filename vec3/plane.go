@@ -0,0 +1,187 @@
+package vec3
+
+import (
+	"fmt"
+)
+
+// PlaneSide is the result of classifying a point or a box against a Plane.
+type PlaneSide int
+
+const (
+	// PlaneOn means the point is on the plane within the given tolerance.
+	PlaneOn PlaneSide = iota
+	// PlaneFront means the point or box is on the side the normal points to.
+	PlaneFront
+	// PlaneBack means the point or box is on the opposite side of the normal.
+	PlaneBack
+	// PlaneStraddling means the box has parts on both sides of the plane.
+	PlaneStraddling
+)
+
+// Plane consists of all points p with Dot(Normal, p) == Distance.
+// If Normal is normalized, Distance is the signed distance of the plane
+// from the origin.
+type Plane struct {
+	Normal   T
+	Distance float32
+}
+
+// PlaneFromPointNormal returns the plane through point with normal.
+// normal will be normalized.
+func PlaneFromPointNormal(point, normal *T) Plane {
+	n := normal.Normalized()
+	return Plane{n, Dot(&n, point)}
+}
+
+// PlaneFromPoints returns the plane through the points a, b and c.
+// The normal points to the side from which a, b, c appear counter clockwise.
+func PlaneFromPoints(a, b, c *T) Plane {
+	ab := Sub(b, a)
+	ac := Sub(c, a)
+	n := Cross(&ab, &ac)
+	n.Normalize()
+	return Plane{n, Dot(&n, a)}
+}
+
+// ParsePlane parses a Plane from a string. See also String()
+func ParsePlane(s string) (r Plane, err error) {
+	_, err = fmt.Sscanf(s, "%f %f %f %f", &r.Normal[0], &r.Normal[1], &r.Normal[2], &r.Distance)
+	return r, err
+}
+
+// String formats Plane as string. See also ParsePlane().
+func (self *Plane) String() string {
+	return fmt.Sprintf("%s %f", self.Normal.String(), self.Distance)
+}
+
+// Normalize scales Normal and Distance so that Normal has a length of one
+// and returns self.
+func (self *Plane) Normalize() *Plane {
+	l := self.Normal.Length()
+	if l == 0 || l == 1 {
+		return self
+	}
+	self.Normal.Scale(1 / l)
+	self.Distance /= l
+	return self
+}
+
+// Normalized returns a normalized copy of the plane.
+func (self *Plane) Normalized() Plane {
+	p := *self
+	return *p.Normalize()
+}
+
+// Flip reverses the direction of the normal without moving the plane
+// and returns self.
+func (self *Plane) Flip() *Plane {
+	self.Normal.Invert()
+	self.Distance = -self.Distance
+	return self
+}
+
+// Flipped returns a copy of the plane with the direction of the normal reversed.
+func (self *Plane) Flipped() Plane {
+	p := *self
+	return *p.Flip()
+}
+
+// SignedDistance returns the distance of p to the plane,
+// which is positive in front and negative behind the plane.
+// The plane has to be normalized.
+func (self *Plane) SignedDistance(p *T) float32 {
+	return Dot(&self.Normal, p) - self.Distance
+}
+
+// ProjectPoint returns the point on the plane that is closest to p.
+// The plane has to be normalized.
+func (self *Plane) ProjectPoint(p *T) T {
+	n := self.Normal.Scaled(self.SignedDistance(p))
+	return Sub(p, &n)
+}
+
+// ReflectPoint returns p mirrored at the plane.
+// The plane has to be normalized.
+func (self *Plane) ReflectPoint(p *T) T {
+	n := self.Normal.Scaled(2 * self.SignedDistance(p))
+	return Sub(p, &n)
+}
+
+// ClassifyPoint returns on which side of the plane p lies.
+// Points within epsilon distance to the plane are classified as PlaneOn.
+// The plane has to be normalized.
+func (self *Plane) ClassifyPoint(p *T, epsilon float32) PlaneSide {
+	d := self.SignedDistance(p)
+	switch {
+	case d > epsilon:
+		return PlaneFront
+	case d < -epsilon:
+		return PlaneBack
+	default:
+		return PlaneOn
+	}
+}
+
+// ClassifyBox returns PlaneFront or PlaneBack if box lies completely
+// on one side of the plane, else PlaneStraddling.
+// The plane has to be normalized.
+func (self *Plane) ClassifyBox(box *Box) PlaneSide {
+	center := box.Center()
+	extents := box.Extents()
+	r := extents[0]*abs(self.Normal[0]) + extents[1]*abs(self.Normal[1]) + extents[2]*abs(self.Normal[2])
+	d := self.SignedDistance(&center)
+	switch {
+	case d > r:
+		return PlaneFront
+	case d < -r:
+		return PlaneBack
+	default:
+		return PlaneStraddling
+	}
+}
+
+// IntersectRay returns the parameter t where ray hits the plane.
+// See also Ray.IntersectPlane.
+func (self *Plane) IntersectRay(ray *Ray) (t float32, ok bool) {
+	return ray.IntersectPlane(&self.Normal, self.Distance)
+}
+
+// IntersectLine returns the point where the infinite line through a and b
+// hits the plane. ok is false if the line is parallel to the plane.
+func (self *Plane) IntersectLine(a, b *T) (point T, ok bool) {
+	ab := Sub(b, a)
+	denom := Dot(&self.Normal, &ab)
+	if denom == 0 {
+		return Zero, false
+	}
+	t := (self.Distance - Dot(&self.Normal, a)) / denom
+	ab.Scale(t)
+	return Add(a, &ab), true
+}
+
+// IntersectPlanes returns the point where the three planes a, b and c meet.
+// ok is false if two of the planes are parallel
+// or all three planes share a common line.
+func IntersectPlanes(a, b, c *Plane) (point T, ok bool) {
+	bc := Cross(&b.Normal, &c.Normal)
+	denom := Dot(&a.Normal, &bc)
+	if denom == 0 {
+		return Zero, false
+	}
+	ca := Cross(&c.Normal, &a.Normal)
+	ab := Cross(&a.Normal, &b.Normal)
+	bc.Scale(a.Distance)
+	ca.Scale(b.Distance)
+	ab.Scale(c.Distance)
+	point = Add(&bc, &ca)
+	point.Add(&ab)
+	point.Scale(1 / denom)
+	return point, true
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
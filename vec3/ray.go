@@ -0,0 +1,152 @@
+package vec3
+
+import (
+	"fmt"
+
+	"github.com/barnex/fmath"
+)
+
+// Ray is a half-line that starts at Origin and extends in direction Dir.
+// Dir does not need to be normalized, the parameter t of all methods
+// is measured in multiples of Dir.
+type Ray struct {
+	Origin T
+	Dir    T
+}
+
+// ParseRay parses a Ray from a string. See also String()
+func ParseRay(s string) (r Ray, err error) {
+	_, err = fmt.Sscanf(s, "%f %f %f %f %f %f", &r.Origin[0], &r.Origin[1], &r.Origin[2], &r.Dir[0], &r.Dir[1], &r.Dir[2])
+	return r, err
+}
+
+// String formats Ray as string. See also ParseRay().
+func (self *Ray) String() string {
+	return self.Origin.String() + " " + self.Dir.String()
+}
+
+// PointAt returns the point Origin + Dir * t.
+func (self *Ray) PointAt(t float32) T {
+	return T{
+		self.Origin[0] + self.Dir[0]*t,
+		self.Origin[1] + self.Dir[1]*t,
+		self.Origin[2] + self.Dir[2]*t,
+	}
+}
+
+// ClosestPointToPoint returns the point on the ray that is closest to p
+// and its parameter t which is never negative.
+func (self *Ray) ClosestPointToPoint(p *T) (T, float32) {
+	lengthSqr := self.Dir.LengthSqr()
+	if lengthSqr == 0 {
+		return self.Origin, 0
+	}
+	op := Sub(p, &self.Origin)
+	t := Dot(&op, &self.Dir) / lengthSqr
+	if t < 0 {
+		t = 0
+	}
+	return self.PointAt(t), t
+}
+
+// DistanceToPoint returns the distance from p to the closest point on the ray.
+func (self *Ray) DistanceToPoint(p *T) float32 {
+	c, _ := self.ClosestPointToPoint(p)
+	d := Sub(p, &c)
+	return d.Length()
+}
+
+// IntersectBox intersects the ray with the box using the slab method.
+// tmin and tmax are the parameters where the ray enters and leaves the box.
+// tmin is negative if the origin is inside of the box.
+// ok is false if the ray misses the box or the box is behind the origin.
+func (self *Ray) IntersectBox(box *Box) (tmin, tmax float32, ok bool) {
+	tmin = -MaxVal[0]
+	tmax = MaxVal[0]
+	for i := 0; i < 3; i++ {
+		ooDir := 1 / self.Dir[i]
+		t0 := (box.Min[i] - self.Origin[i]) * ooDir
+		t1 := (box.Max[i] - self.Origin[i]) * ooDir
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		if t0 > tmin {
+			tmin = t0
+		}
+		if t1 < tmax {
+			tmax = t1
+		}
+		if tmin > tmax {
+			return tmin, tmax, false
+		}
+	}
+	return tmin, tmax, tmax >= 0
+}
+
+// IntersectSphere returns the smallest non negative parameter t where the ray
+// hits the sphere with center and radius. If the origin is inside of
+// the sphere, t is where the ray leaves the sphere.
+// ok is false if the ray misses the sphere or the sphere is behind the origin.
+func (self *Ray) IntersectSphere(center *T, radius float32) (t float32, ok bool) {
+	oc := Sub(&self.Origin, center)
+	a := self.Dir.LengthSqr()
+	b := Dot(&oc, &self.Dir)
+	c := oc.LengthSqr() - radius*radius
+	discriminant := b*b - a*c
+	if a == 0 || discriminant < 0 {
+		return 0, false
+	}
+	sqrtD := fmath.Sqrt(discriminant)
+	t = (-b - sqrtD) / a
+	if t < 0 {
+		t = (-b + sqrtD) / a
+		if t < 0 {
+			return 0, false
+		}
+	}
+	return t, true
+}
+
+// IntersectPlane returns the parameter t where the ray hits the plane
+// of all points p with Dot(normal, p) == distance.
+// ok is false if the ray is parallel to the plane or the plane is behind the origin.
+func (self *Ray) IntersectPlane(normal *T, distance float32) (t float32, ok bool) {
+	denom := Dot(normal, &self.Dir)
+	if denom == 0 {
+		return 0, false
+	}
+	t = (distance - Dot(normal, &self.Origin)) / denom
+	return t, t >= 0
+}
+
+// IntersectTriangle intersects the ray with the triangle a, b, c
+// using the Möller-Trumbore algorithm. Both sides of the triangle are hit.
+// u and v are the barycentric coordinates of the hit point,
+// which is a*(1-u-v) + b*u + c*v.
+// ok is false if the ray misses the triangle or the triangle is behind the origin.
+func (self *Ray) IntersectTriangle(a, b, c *T) (t, u, v float32, ok bool) {
+	const epsilon = 1e-7 //gend:float64 1e-12
+	ab := Sub(b, a)
+	ac := Sub(c, a)
+	p := Cross(&self.Dir, &ac)
+	det := Dot(&ab, &p)
+	if det > -epsilon && det < epsilon {
+		return 0, 0, 0, false
+	}
+	ooDet := 1 / det
+	ao := Sub(&self.Origin, a)
+	u = Dot(&ao, &p) * ooDet
+	if u < 0 || u > 1 {
+		return 0, 0, 0, false
+	}
+	q := Cross(&ao, &ab)
+	v = Dot(&self.Dir, &q) * ooDet
+	if v < 0 || u+v > 1 {
+		return 0, 0, 0, false
+	}
+	t = Dot(&ac, &q) * ooDet
+	if t < 0 {
+		return 0, 0, 0, false
+	}
+	return t, u, v, true
+}
@@ -0,0 +1,124 @@
+package vec3
+
+import (
+	"fmt"
+)
+
+// Segment is the line segment from A to B.
+// The parameter t of the methods is 0 at A and 1 at B.
+type Segment struct {
+	A T
+	B T
+}
+
+// ParseSegment parses a Segment from a string. See also String()
+func ParseSegment(s string) (r Segment, err error) {
+	_, err = fmt.Sscanf(s, "%f %f %f %f %f %f", &r.A[0], &r.A[1], &r.A[2], &r.B[0], &r.B[1], &r.B[2])
+	return r, err
+}
+
+// String formats Segment as string. See also ParseSegment().
+func (self *Segment) String() string {
+	return self.A.String() + " " + self.B.String()
+}
+
+// Length returns the length of the segment.
+func (self *Segment) Length() float32 {
+	d := Sub(&self.B, &self.A)
+	return d.Length()
+}
+
+// PointAt returns the point A + (B - A) * t.
+func (self *Segment) PointAt(t float32) T {
+	return T{
+		self.A[0] + (self.B[0]-self.A[0])*t,
+		self.A[1] + (self.B[1]-self.A[1])*t,
+		self.A[2] + (self.B[2]-self.A[2])*t,
+	}
+}
+
+// Bounds returns the axis aligned bounding box of the segment.
+func (self *Segment) Bounds() Box {
+	return Box{Min(&self.A, &self.B), Max(&self.A, &self.B)}
+}
+
+// Support returns the end point of the segment that is farthest in direction dir.
+func (self *Segment) Support(dir *T) T {
+	if Dot(&self.B, dir) > Dot(&self.A, dir) {
+		return self.B
+	}
+	return self.A
+}
+
+// ClosestPoint returns the point on the segment that is closest to p
+// and its parameter t.
+func (self *Segment) ClosestPoint(p *T) (T, float32) {
+	ab := Sub(&self.B, &self.A)
+	lengthSqr := ab.LengthSqr()
+	if lengthSqr == 0 {
+		return self.A, 0
+	}
+	ap := Sub(p, &self.A)
+	t := clamp(Dot(&ap, &ab)/lengthSqr, 0, 1)
+	return self.PointAt(t), t
+}
+
+// DistanceToPoint returns the distance from p to the segment.
+func (self *Segment) DistanceToPoint(p *T) float32 {
+	c, _ := self.ClosestPoint(p)
+	d := Sub(p, &c)
+	return d.Length()
+}
+
+// DistanceToPointSqr returns the squared distance from p to the segment.
+func (self *Segment) DistanceToPointSqr(p *T) float32 {
+	c, _ := self.ClosestPoint(p)
+	d := Sub(p, &c)
+	return d.LengthSqr()
+}
+
+// ClosestPoints returns the points p on self and q on other that are
+// closest to each other together with their parameters s and t.
+// For parallel segments one of the closest pairs is returned.
+func (self *Segment) ClosestPoints(other *Segment) (s, t float32, p, q T) {
+	d1 := Sub(&self.B, &self.A)
+	d2 := Sub(&other.B, &other.A)
+	r := Sub(&self.A, &other.A)
+	a := d1.LengthSqr()
+	e := d2.LengthSqr()
+	f := Dot(&d2, &r)
+
+	switch {
+	case a == 0 && e == 0:
+		return 0, 0, self.A, other.A
+	case a == 0:
+		t = clamp(f/e, 0, 1)
+	default:
+		c := Dot(&d1, &r)
+		if e == 0 {
+			s = clamp(-c/a, 0, 1)
+		} else {
+			b := Dot(&d1, &d2)
+			denom := a*e - b*b
+			if denom != 0 {
+				s = clamp((b*f-c*e)/denom, 0, 1)
+			}
+			t = (b*s + f) / e
+			if t < 0 {
+				t = 0
+				s = clamp(-c/a, 0, 1)
+			} else if t > 1 {
+				t = 1
+				s = clamp((b-c)/a, 0, 1)
+			}
+		}
+	}
+	return s, t, self.PointAt(s), other.PointAt(t)
+}
+
+// DistanceToSegment returns the smallest distance between self and other.
+func (self *Segment) DistanceToSegment(other *Segment) float32 {
+	_, _, p, q := self.ClosestPoints(other)
+	d := Sub(&p, &q)
+	return d.Length()
+}
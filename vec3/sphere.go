@@ -0,0 +1,297 @@
+package vec3
+
+import (
+	"fmt"
+	"math"
+)
+
+var (
+	// EmptySphere has a negative radius and contains no points.
+	// It is the identity for Sphere.Merge.
+	EmptySphere = Sphere{Zero, -1}
+)
+
+// sphereEpsilon is the relative tolerance used to decide whether
+// points lie inside of a sphere while computing enclosing spheres.
+const sphereEpsilon = 1e-5 //gend:float64 1e-10
+
+type Sphere struct {
+	Center T
+	Radius float32
+}
+
+// SphereFromBox returns the smallest sphere that contains box.
+func SphereFromBox(box *Box) Sphere {
+	extents := box.Extents()
+	return Sphere{box.Center(), extents.Length()}
+}
+
+// RitterSphere returns an approximate bounding sphere of points
+// using Ritter's algorithm. The result is typically 5 to 20 percent
+// larger than the minimal sphere, but it is calculated in linear time.
+// See also MinimalSphere.
+func RitterSphere(points []T) Sphere {
+	if len(points) == 0 {
+		return EmptySphere
+	}
+	x := farthestPoint(points, &points[0])
+	y := farthestPoint(points, x)
+	s := sphereFromDiameter(x, y)
+	for i := range points {
+		s.ExtendByPoint(&points[i])
+	}
+	return s
+}
+
+// MinimalSphere returns the smallest sphere that contains all points
+// using Welzl's algorithm with the move-to-front heuristic.
+// The order of points is not changed and the result is deterministic.
+// See also RitterSphere.
+func MinimalSphere(points []T) Sphere {
+	if len(points) == 0 {
+		return EmptySphere
+	}
+	pts := make([]T, len(points))
+	copy(pts, points)
+	var support [4]T
+	return minimalSphere(pts, len(pts), support[:0])
+}
+
+// minimalSphere returns the smallest sphere that contains pts[:end]
+// and has all points of support on its surface. Points that are not
+// contained by the current sphere are moved to the front of pts
+// which speeds up the following calls.
+func minimalSphere(pts []T, end int, support []T) Sphere {
+	s := sphereFromSupport(support)
+	if len(support) == 4 {
+		return s
+	}
+	for i := 0; i < end; i++ {
+		if s.containsPointEpsilon(&pts[i]) {
+			continue
+		}
+		p := pts[i]
+		s = minimalSphere(pts, i, append(support, p))
+		copy(pts[1:i+1], pts[:i])
+		pts[0] = p
+	}
+	return s
+}
+
+// sphereFromSupport returns the smallest sphere
+// that has all points of support on its surface.
+func sphereFromSupport(support []T) Sphere {
+	switch len(support) {
+	case 0:
+		return EmptySphere
+	case 1:
+		return Sphere{support[0], 0}
+	case 2:
+		return sphereFromDiameter(&support[0], &support[1])
+	case 3:
+		return circumsphere3(&support[0], &support[1], &support[2])
+	default:
+		return circumsphere4(&support[0], &support[1], &support[2], &support[3])
+	}
+}
+
+func sphereFromDiameter(a, b *T) Sphere {
+	center := Add(a, b)
+	center.Scale(0.5)
+	d := Sub(b, a)
+	return Sphere{center, d.Length() * 0.5}
+}
+
+// circumsphere3 returns the smallest sphere through a, b and c.
+// For collinear points the sphere through the two farthest points is returned.
+func circumsphere3(a, b, c *T) Sphere {
+	ab := Sub(b, a)
+	ac := Sub(c, a)
+	n := Cross(&ab, &ac)
+	nl := n.LengthSqr()
+	if nl <= sphereEpsilon*sphereEpsilon*ab.LengthSqr()*ac.LengthSqr() {
+		s := sphereFromDiameter(a, b)
+		if t := sphereFromDiameter(a, c); t.Radius > s.Radius {
+			s = t
+		}
+		if t := sphereFromDiameter(b, c); t.Radius > s.Radius {
+			s = t
+		}
+		return s
+	}
+	nab := Cross(&n, &ab)
+	acn := Cross(&ac, &n)
+	nab.Scale(ac.LengthSqr())
+	acn.Scale(ab.LengthSqr())
+	offset := Add(&nab, &acn)
+	offset.Scale(1 / (2 * nl))
+	return Sphere{Add(a, &offset), offset.Length()}
+}
+
+// circumsphere4 returns the sphere through a, b, c and d.
+// For coplanar points the smallest sphere through three
+// of the points that contains the fourth one is returned.
+func circumsphere4(a, b, c, d *T) Sphere {
+	ab := Sub(b, a)
+	ac := Sub(c, a)
+	ad := Sub(d, a)
+	acad := Cross(&ac, &ad)
+	denom := 2 * Dot(&ab, &acad)
+	scale := ab.Length() * ac.Length() * ad.Length()
+	if abs(denom) <= sphereEpsilon*scale {
+		best := EmptySphere
+		candidates := [4]Sphere{
+			circumsphere3(a, b, c),
+			circumsphere3(a, b, d),
+			circumsphere3(a, c, d),
+			circumsphere3(b, c, d),
+		}
+		for i := range candidates {
+			s := &candidates[i]
+			if s.containsPointEpsilon(a) && s.containsPointEpsilon(b) &&
+				s.containsPointEpsilon(c) && s.containsPointEpsilon(d) &&
+				(best.IsEmpty() || s.Radius < best.Radius) {
+				best = *s
+			}
+		}
+		return best
+	}
+	abac := Cross(&ab, &ac)
+	adab := Cross(&ad, &ab)
+	abac.Scale(ad.LengthSqr())
+	adab.Scale(ac.LengthSqr())
+	acad.Scale(ab.LengthSqr())
+	offset := Add(&abac, &adab)
+	offset.Add(&acad)
+	offset.Scale(1 / denom)
+	return Sphere{Add(a, &offset), offset.Length()}
+}
+
+func farthestPoint(points []T, from *T) *T {
+	farthest := &points[0]
+	maxDist := float32(-1)
+	for i := range points {
+		d := Sub(&points[i], from)
+		if l := d.LengthSqr(); l > maxDist {
+			maxDist = l
+			farthest = &points[i]
+		}
+	}
+	return farthest
+}
+
+// ParseSphere parses a Sphere from a string. See also String()
+func ParseSphere(s string) (r Sphere, err error) {
+	_, err = fmt.Sscanf(s, "%f %f %f %f", &r.Center[0], &r.Center[1], &r.Center[2], &r.Radius)
+	return r, err
+}
+
+// String formats Sphere as string. See also ParseSphere().
+func (self *Sphere) String() string {
+	return fmt.Sprintf("%s %f", self.Center.String(), self.Radius)
+}
+
+// IsEmpty returns true if the sphere has a negative radius.
+func (self *Sphere) IsEmpty() bool {
+	return self.Radius < 0
+}
+
+func (self *Sphere) ContainsPoint(p *T) bool {
+	d := Sub(p, &self.Center)
+	return d.LengthSqr() <= self.Radius*self.Radius && !self.IsEmpty()
+}
+
+func (self *Sphere) containsPointEpsilon(p *T) bool {
+	d := Sub(p, &self.Center)
+	r := self.Radius * (1 + sphereEpsilon)
+	return d.LengthSqr() <= r*r && !self.IsEmpty()
+}
+
+// Contains returns true if other lies completely inside of self.
+func (self *Sphere) Contains(other *Sphere) bool {
+	if other.IsEmpty() {
+		return true
+	}
+	d := Sub(&other.Center, &self.Center)
+	return d.Length()+other.Radius <= self.Radius
+}
+
+// Support returns the point of the sphere that is farthest in direction dir.
+func (self *Sphere) Support(dir *T) T {
+	l := dir.Length()
+	if l == 0 {
+		return T{self.Center[0] + self.Radius, self.Center[1], self.Center[2]}
+	}
+	s := dir.Scaled(self.Radius / l)
+	return Add(&self.Center, &s)
+}
+
+// Intersects returns true if self and other overlap or touch.
+func (self *Sphere) Intersects(other *Sphere) bool {
+	d := Sub(&other.Center, &self.Center)
+	r := self.Radius + other.Radius
+	return d.LengthSqr() <= r*r && !self.IsEmpty() && !other.IsEmpty()
+}
+
+// IntersectsBox returns true if the sphere and box overlap or touch.
+func (self *Sphere) IntersectsBox(box *Box) bool {
+	return box.DistanceToPointSqr(&self.Center) <= self.Radius*self.Radius && !self.IsEmpty()
+}
+
+// ExtendByPoint grows the sphere so that it contains p and returns self.
+// The sphere is moved towards p as little as possible.
+func (self *Sphere) ExtendByPoint(p *T) *Sphere {
+	if self.IsEmpty() {
+		*self = Sphere{*p, 0}
+		return self
+	}
+	d := Sub(p, &self.Center)
+	dist := d.Length()
+	if dist <= self.Radius {
+		return self
+	}
+	radius := (self.Radius + dist) * 0.5
+	d.Scale((radius - self.Radius) / dist)
+	self.Center.Add(&d)
+	self.Radius = radius
+	return self
+}
+
+// Merge grows the sphere to the smallest sphere that contains self and other
+// and returns self.
+func (self *Sphere) Merge(other *Sphere) *Sphere {
+	if self.Contains(other) {
+		return self
+	}
+	if other.Contains(self) {
+		*self = *other
+		return self
+	}
+	d := Sub(&other.Center, &self.Center)
+	dist := d.Length()
+	radius := (self.Radius + dist + other.Radius) * 0.5
+	d.Scale((radius - self.Radius) / dist)
+	self.Center.Add(&d)
+	self.Radius = radius
+	return self
+}
+
+// Merged returns the smallest sphere that contains self and other.
+func (self *Sphere) Merged(other *Sphere) Sphere {
+	s := *self
+	return *s.Merge(other)
+}
+
+// Bounds returns the axis aligned bounding box of the sphere.
+func (self *Sphere) Bounds() Box {
+	r := T{self.Radius, self.Radius, self.Radius}
+	return Box{Sub(&self.Center, &r), Add(&self.Center, &r)}
+}
+
+// Volume returns the volume of the sphere or zero if the sphere is empty.
+func (self *Sphere) Volume() float32 {
+	if self.IsEmpty() {
+		return 0
+	}
+	return 4.0 / 3.0 * math.Pi * self.Radius * self.Radius * self.Radius
+}
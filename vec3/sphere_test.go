@@ -0,0 +1,209 @@
+package vec3
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/barnex/fmath"
+)
+
+// sphereTestEpsilon is the relative tolerance of the sphere tests.
+const sphereTestEpsilon = 1e-4 //gend:float64 1e-9
+
+func randomTestPoint(r *rand.Rand) T {
+	return T{float32(r.Float64()*2 - 1), float32(r.Float64()*2 - 1), float32(r.Float64()*2 - 1)}
+}
+
+// containsAll checks that s contains points within the test tolerance.
+func containsAll(t *testing.T, name string, s *Sphere, points []T) {
+	for i := range points {
+		d := Sub(&points[i], &s.Center)
+		if d.Length() > s.Radius*(1+sphereTestEpsilon)+sphereTestEpsilon {
+			t.Errorf("%s: %v does not contain point %d %v", name, s, i, points[i])
+			return
+		}
+	}
+}
+
+// bruteForceSphere returns the smallest sphere through 1 to 4 of the points
+// that contains all points.
+func bruteForceSphere(points []T) Sphere {
+	best := EmptySphere
+	try := func(s Sphere) {
+		for i := range points {
+			d := Sub(&points[i], &s.Center)
+			if d.Length() > s.Radius*(1+sphereTestEpsilon)+sphereTestEpsilon {
+				return
+			}
+		}
+		if best.IsEmpty() || s.Radius < best.Radius {
+			best = s
+		}
+	}
+	n := len(points)
+	for i := 0; i < n; i++ {
+		try(Sphere{points[i], 0})
+		for j := i + 1; j < n; j++ {
+			try(sphereFromDiameter(&points[i], &points[j]))
+			for k := j + 1; k < n; k++ {
+				try(circumsphere3(&points[i], &points[j], &points[k]))
+				for l := k + 1; l < n; l++ {
+					try(circumsphere4(&points[i], &points[j], &points[k], &points[l]))
+				}
+			}
+		}
+	}
+	return best
+}
+
+func checkSpheres(t *testing.T, name string, points []T) {
+	original := make([]T, len(points))
+	copy(original, points)
+
+	minimal := MinimalSphere(points)
+	ritter := RitterSphere(points)
+	for i := range points {
+		if points[i] != original[i] {
+			t.Fatalf("%s: MinimalSphere changed the order of the points", name)
+		}
+	}
+	if len(points) == 0 {
+		if !minimal.IsEmpty() || !ritter.IsEmpty() {
+			t.Errorf("%s: got %v and %v, want empty spheres", name, minimal, ritter)
+		}
+		return
+	}
+
+	containsAll(t, name+" MinimalSphere", &minimal, points)
+	containsAll(t, name+" RitterSphere", &ritter, points)
+	if ritter.Radius < minimal.Radius*(1-sphereTestEpsilon) {
+		t.Errorf("%s: RitterSphere %v is smaller than MinimalSphere %v", name, ritter, minimal)
+	}
+	if again := MinimalSphere(points); again != minimal {
+		t.Errorf("%s: MinimalSphere is not deterministic: %v and %v", name, minimal, again)
+	}
+	if len(points) <= 12 {
+		want := bruteForceSphere(points)
+		if minimal.Radius > want.Radius*(1+sphereTestEpsilon)+sphereTestEpsilon {
+			t.Errorf("%s: MinimalSphere %v is larger than the brute force minimum %v", name, minimal, want)
+		}
+	}
+}
+
+func TestMinimalSphere(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []T
+		want   Sphere
+	}{
+		{"no points", nil, EmptySphere},
+		{"one point", []T{{1, 2, 3}}, Sphere{T{1, 2, 3}, 0}},
+		{"two points", []T{{1, 0, 0}, {-1, 0, 0}}, Sphere{Zero, 1}},
+		{"duplicates", []T{{1, 2, 3}, {1, 2, 3}, {1, 2, 3}}, Sphere{T{1, 2, 3}, 0}},
+		{"duplicate pairs", []T{{0, 0, 2}, {0, 0, -2}, {0, 0, 2}, {0, 0, -2}, {0, 0, 2}}, Sphere{Zero, 2}},
+		{"collinear", []T{{0, 1, 0}, {0, 3, 0}, {0, -1, 0}, {0, 2, 0}}, Sphere{T{0, 1, 0}, 2}},
+		{"coplanar square", []T{{1, 1, 0}, {-1, 1, 0}, {-1, -1, 0}, {1, -1, 0}, {0, 0, 0}}, Sphere{Zero, fmath.Sqrt(2)}},
+		{"obtuse triangle", []T{{-2, 0, 0}, {2, 0, 0}, {0, 0.5, 0}}, Sphere{Zero, 2}},
+		{"tetrahedron", []T{{1, 1, 1}, {1, -1, -1}, {-1, 1, -1}, {-1, -1, 1}}, Sphere{Zero, fmath.Sqrt(3)}},
+	}
+	for _, test := range tests {
+		checkSpheres(t, test.name, test.points)
+		s := MinimalSphere(test.points)
+		d := Sub(&s.Center, &test.want.Center)
+		if d.Length() > sphereTestEpsilon || fmath.Abs(s.Radius-test.want.Radius) > sphereTestEpsilon {
+			t.Errorf("%s: MinimalSphere = %v, want %v", test.name, s, test.want)
+		}
+	}
+}
+
+func TestMinimalSphereRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 300; n++ {
+		// Random points.
+		points := make([]T, 1+r.Intn(12))
+		for i := range points {
+			points[i] = randomTestPoint(r)
+		}
+		checkSpheres(t, "random", points)
+
+		// Collinear points.
+		a, b := randomTestPoint(r), randomTestPoint(r)
+		ab := Sub(&b, &a)
+		for i := range points {
+			u := ab.Scaled(float32(r.Float64()))
+			points[i] = Add(&a, &u)
+		}
+		checkSpheres(t, "collinear", points)
+
+		// Coplanar points.
+		c := randomTestPoint(r)
+		ac := Sub(&c, &a)
+		for i := range points {
+			u, v := ab.Scaled(float32(r.Float64())), ac.Scaled(float32(r.Float64()))
+			points[i] = Add(&a, &u)
+			points[i].Add(&v)
+		}
+		checkSpheres(t, "coplanar", points)
+
+		// Duplicate points.
+		for i := range points {
+			points[i] = points[r.Intn(i+1)]
+		}
+		checkSpheres(t, "duplicates", points)
+	}
+}
+
+func TestMinimalSphereOnSphere(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	center := T{1, 2, 3}
+	const radius = 5
+	for _, count := range []int{4, 10, 100, 1000} {
+		points := make([]T, count)
+		for i := range points {
+			p := randomTestPoint(r)
+			for p.LengthSqr() == 0 {
+				p = randomTestPoint(r)
+			}
+			p.Normalize().Scale(radius)
+			points[i] = Add(&center, &p)
+		}
+		checkSpheres(t, "on sphere", points)
+		s := MinimalSphere(points)
+		if s.Radius > radius*(1+sphereTestEpsilon) {
+			t.Errorf("MinimalSphere of %d points on a sphere with radius %d has radius %f", count, radius, s.Radius)
+		}
+	}
+}
+
+func TestCircumsphereFallbacks(t *testing.T) {
+	// Collinear points use the sphere through the two farthest points.
+	a, b, c := T{0, 0, 0}, T{4, 0, 0}, T{1, 0, 0}
+	if s := circumsphere3(&a, &b, &c); s != (Sphere{T{2, 0, 0}, 2}) {
+		t.Errorf("circumsphere3 of collinear points = %v", s)
+	}
+	if s := circumsphere3(&c, &a, &b); s != (Sphere{T{2, 0, 0}, 2}) {
+		t.Errorf("circumsphere3 of collinear points = %v", s)
+	}
+
+	// Coplanar points use the smallest sphere through three of the points
+	// that contains the fourth one.
+	points := []T{{1, 0, 0}, {0, 1, 0}, {-1, 0, 0}, {0, -0.5, 0}}
+	s := circumsphere4(&points[0], &points[1], &points[2], &points[3])
+	containsAll(t, "circumsphere4 of coplanar points", &s, points)
+	if s.Radius > 1+sphereTestEpsilon {
+		t.Errorf("circumsphere4 of coplanar points = %v, want radius 1", s)
+	}
+}
+
+func TestRitterSphere(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for n := 0; n < 100; n++ {
+		points := make([]T, 1+r.Intn(500))
+		for i := range points {
+			points[i] = randomTestPoint(r)
+			points[i][r.Intn(3)] *= 10
+		}
+		s := RitterSphere(points)
+		containsAll(t, "RitterSphere", &s, points)
+	}
+}
@@ -0,0 +1,279 @@
+package vec3
+
+import (
+	"fmt"
+)
+
+// Triangle is the triangle with the corners A, B and C.
+// The front side is the one from which A, B, C appear counter clockwise.
+type Triangle struct {
+	A T
+	B T
+	C T
+}
+
+// ParseTriangle parses a Triangle from a string. See also String()
+func ParseTriangle(s string) (r Triangle, err error) {
+	_, err = fmt.Sscanf(s, "%f %f %f %f %f %f %f %f %f",
+		&r.A[0], &r.A[1], &r.A[2],
+		&r.B[0], &r.B[1], &r.B[2],
+		&r.C[0], &r.C[1], &r.C[2],
+	)
+	return r, err
+}
+
+// String formats Triangle as string. See also ParseTriangle().
+func (self *Triangle) String() string {
+	return self.A.String() + " " + self.B.String() + " " + self.C.String()
+}
+
+// Normal returns the normalized normal of the front side of the triangle.
+func (self *Triangle) Normal() T {
+	ab := Sub(&self.B, &self.A)
+	ac := Sub(&self.C, &self.A)
+	n := Cross(&ab, &ac)
+	return n.Normalized()
+}
+
+// Plane returns the plane of the triangle with the normal of the front side.
+func (self *Triangle) Plane() Plane {
+	return PlaneFromPoints(&self.A, &self.B, &self.C)
+}
+
+// Area returns the area of the triangle.
+func (self *Triangle) Area() float32 {
+	ab := Sub(&self.B, &self.A)
+	ac := Sub(&self.C, &self.A)
+	n := Cross(&ab, &ac)
+	return n.Length() * 0.5
+}
+
+// Centroid returns the center of mass of the triangle.
+func (self *Triangle) Centroid() T {
+	return T{
+		(self.A[0] + self.B[0] + self.C[0]) / 3,
+		(self.A[1] + self.B[1] + self.C[1]) / 3,
+		(self.A[2] + self.B[2] + self.C[2]) / 3,
+	}
+}
+
+// Bounds returns the axis aligned bounding box of the triangle.
+func (self *Triangle) Bounds() Box {
+	box := Box{self.A, self.A}
+	box.ExtendByPoint(&self.B)
+	box.ExtendByPoint(&self.C)
+	return box
+}
+
+// Support returns the corner of the triangle that is farthest in direction dir.
+func (self *Triangle) Support(dir *T) T {
+	s := self.A
+	d := Dot(&self.A, dir)
+	if db := Dot(&self.B, dir); db > d {
+		s = self.B
+		d = db
+	}
+	if dc := Dot(&self.C, dir); dc > d {
+		s = self.C
+	}
+	return s
+}
+
+// Barycentric returns the barycentric coordinates u, v, w of p
+// projected onto the plane of the triangle, so that
+// PointFromBarycentric(u, v, w) is that projected point.
+// The triangle must not be degenerate.
+func (self *Triangle) Barycentric(p *T) (u, v, w float32) {
+	ab := Sub(&self.B, &self.A)
+	ac := Sub(&self.C, &self.A)
+	ap := Sub(p, &self.A)
+	d00 := Dot(&ab, &ab)
+	d01 := Dot(&ab, &ac)
+	d11 := Dot(&ac, &ac)
+	d20 := Dot(&ap, &ab)
+	d21 := Dot(&ap, &ac)
+	ooDenom := 1 / (d00*d11 - d01*d01)
+	v = (d11*d20 - d01*d21) * ooDenom
+	w = (d00*d21 - d01*d20) * ooDenom
+	return 1 - v - w, v, w
+}
+
+// PointFromBarycentric returns the point A*u + B*v + C*w.
+func (self *Triangle) PointFromBarycentric(u, v, w float32) T {
+	return T{
+		self.A[0]*u + self.B[0]*v + self.C[0]*w,
+		self.A[1]*u + self.B[1]*v + self.C[1]*w,
+		self.A[2]*u + self.B[2]*v + self.C[2]*w,
+	}
+}
+
+// ContainsPoint returns true if p lies inside of the triangle or on its border.
+// p is assumed to lie in the plane of the triangle, else it is projected onto it.
+func (self *Triangle) ContainsPoint(p *T) bool {
+	u, v, w := self.Barycentric(p)
+	return u >= 0 && v >= 0 && w >= 0
+}
+
+// ClosestPoint returns the point on the triangle that is closest to p.
+func (self *Triangle) ClosestPoint(p *T) T {
+	a, b, c := &self.A, &self.B, &self.C
+	ab := Sub(b, a)
+	ac := Sub(c, a)
+	ap := Sub(p, a)
+	d1 := Dot(&ab, &ap)
+	d2 := Dot(&ac, &ap)
+	if d1 <= 0 && d2 <= 0 {
+		return *a
+	}
+
+	bp := Sub(p, b)
+	d3 := Dot(&ab, &bp)
+	d4 := Dot(&ac, &bp)
+	if d3 >= 0 && d4 <= d3 {
+		return *b
+	}
+
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return self.PointFromBarycentric(1-d1/(d1-d3), d1/(d1-d3), 0)
+	}
+
+	cp := Sub(p, c)
+	d5 := Dot(&ab, &cp)
+	d6 := Dot(&ac, &cp)
+	if d6 >= 0 && d5 <= d6 {
+		return *c
+	}
+
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return self.PointFromBarycentric(1-d2/(d2-d6), 0, d2/(d2-d6))
+	}
+
+	va := d3*d6 - d5*d4
+	if va <= 0 && (d4-d3) >= 0 && (d5-d6) >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return self.PointFromBarycentric(0, 1-w, w)
+	}
+
+	ooDenom := 1 / (va + vb + vc)
+	v := vb * ooDenom
+	w := vc * ooDenom
+	return self.PointFromBarycentric(1-v-w, v, w)
+}
+
+// Intersects returns true if self and other overlap or touch.
+// It uses the separating axis theorem, which also handles coplanar triangles.
+func (self *Triangle) Intersects(other *Triangle) bool {
+	edges := self.edges()
+	otherEdges := other.edges()
+	n := Cross(&edges[0], &edges[1])
+	otherN := Cross(&otherEdges[0], &otherEdges[1])
+
+	if self.separatedOnAxis(other, &n) || self.separatedOnAxis(other, &otherN) {
+		return false
+	}
+	if nn := Cross(&n, &otherN); isNearZero(&nn, &n, &otherN) {
+		// The triangles are coplanar, so test the edge normals in the plane.
+		for i := range edges {
+			axis := Cross(&n, &edges[i])
+			if self.separatedOnAxis(other, &axis) {
+				return false
+			}
+			axis = Cross(&n, &otherEdges[i])
+			if self.separatedOnAxis(other, &axis) {
+				return false
+			}
+		}
+		return true
+	}
+	for i := range edges {
+		for j := range otherEdges {
+			axis := Cross(&edges[i], &otherEdges[j])
+			if !isNearZero(&axis, &edges[i], &otherEdges[j]) && self.separatedOnAxis(other, &axis) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IntersectsBox returns true if the triangle and box overlap or touch.
+// It uses the separating axis test of Akenine-Möller.
+func (self *Triangle) IntersectsBox(box *Box) bool {
+	center := box.Center()
+	extents := box.Extents()
+	t := Triangle{Sub(&self.A, &center), Sub(&self.B, &center), Sub(&self.C, &center)}
+
+	// The face normals of the box.
+	for i := 0; i < 3; i++ {
+		min, max := minMax3(t.A[i], t.B[i], t.C[i])
+		if min > extents[i] || max < -extents[i] {
+			return false
+		}
+	}
+
+	// The cross products of the triangle edges with the box axes.
+	edges := t.edges()
+	for i := range edges {
+		for j := 0; j < 3; j++ {
+			var unit T
+			unit[j] = 1
+			axis := Cross(&edges[i], &unit)
+			if t.separatedFromBoxOnAxis(&extents, &axis) {
+				return false
+			}
+		}
+	}
+
+	// The normal of the triangle.
+	n := Cross(&edges[0], &edges[1])
+	return !t.separatedFromBoxOnAxis(&extents, &n)
+}
+
+func (self *Triangle) edges() [3]T {
+	return [3]T{
+		Sub(&self.B, &self.A),
+		Sub(&self.C, &self.B),
+		Sub(&self.A, &self.C),
+	}
+}
+
+func (self *Triangle) project(axis *T) (min, max float32) {
+	return minMax3(Dot(&self.A, axis), Dot(&self.B, axis), Dot(&self.C, axis))
+}
+
+func (self *Triangle) separatedOnAxis(other *Triangle, axis *T) bool {
+	min, max := self.project(axis)
+	otherMin, otherMax := other.project(axis)
+	return min > otherMax || max < otherMin
+}
+
+// separatedFromBoxOnAxis tests the triangle against a box
+// centered at the origin with the half size extents.
+func (self *Triangle) separatedFromBoxOnAxis(extents, axis *T) bool {
+	min, max := self.project(axis)
+	r := extents[0]*abs(axis[0]) + extents[1]*abs(axis[1]) + extents[2]*abs(axis[2])
+	return min > r || max < -r
+}
+
+// isNearZero returns true if the cross product of a and b is
+// too small to be used as separating axis.
+func isNearZero(cross, a, b *T) bool {
+	return cross.LengthSqr() <= 1e-12*a.LengthSqr()*b.LengthSqr() //gend:float64 1e-24
+}
+
+func minMax3(a, b, c float32) (min, max float32) {
+	min, max = a, a
+	if b < min {
+		min = b
+	} else if b > max {
+		max = b
+	}
+	if c < min {
+		min = c
+	} else if c > max {
+		max = c
+	}
+	return min, max
+}
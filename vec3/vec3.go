@@ -74,6 +74,11 @@ func (self *T) Get(col, row int) float32 {
 	return self[row]
 }
 
+// Set sets one element of the vector.
+func (self *T) Set(col, row int, value float32) {
+	self[row] = value
+}
+
 // IsZero checks if all elements of the vector are zero.
 func (self *T) IsZero() bool {
 	return self[0] == 0 && self[1] == 0 && self[2] == 0
@@ -92,10 +97,11 @@ func (self *T) LengthSqr() float32 {
 }
 
 // Scale multiplies all element of the vector by f and returns self.
-func (self *T) Scale(f float32) {
+func (self *T) Scale(f float32) *T {
 	self[0] *= f
 	self[1] *= f
 	self[2] *= f
+	return self
 }
 
 // Scaled returns a copy of self with all elements multiplies by f.
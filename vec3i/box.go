@@ -0,0 +1,122 @@
+package vec3i
+
+import (
+	"fmt"
+
+	"github.com/ungerik/go3d/vec3"
+)
+
+// Box is a box of grid cells from Min including to Max excluding.
+type Box struct {
+	Min T
+	Max T
+}
+
+// BoxCovering returns the smallest Box containing all cells overlapped by box.
+func BoxCovering(box *vec3.Box) Box {
+	max := Floor(&box.Max)
+	return Box{Floor(&box.Min), T{max[0] + 1, max[1] + 1, max[2] + 1}}
+}
+
+// ParseBox parses a Box from a string. See also String()
+func ParseBox(s string) (r Box, err error) {
+	_, err = fmt.Sscanf(s, "%d %d %d %d %d %d", &r.Min[0], &r.Min[1], &r.Min[2], &r.Max[0], &r.Max[1], &r.Max[2])
+	return r, err
+}
+
+// String formats Box as string. See also ParseBox().
+func (self *Box) String() string {
+	return self.Min.String() + " " + self.Max.String()
+}
+
+// IsEmpty returns true if the box contains no cells.
+func (self *Box) IsEmpty() bool {
+	return self.Max[0] <= self.Min[0] || self.Max[1] <= self.Min[1] || self.Max[2] <= self.Min[2]
+}
+
+// Size returns the number of cells along every axis.
+func (self *Box) Size() T {
+	if self.IsEmpty() {
+		return Zero
+	}
+	return Sub(&self.Max, &self.Min)
+}
+
+// Volume returns the number of cells in the box.
+func (self *Box) Volume() int {
+	size := self.Size()
+	return int(size[0]) * int(size[1]) * int(size[2])
+}
+
+// ContainsPoint returns if the cell p is inside the box.
+func (self *Box) ContainsPoint(p *T) bool {
+	return p[0] >= self.Min[0] && p[0] < self.Max[0] &&
+		p[1] >= self.Min[1] && p[1] < self.Max[1] &&
+		p[2] >= self.Min[2] && p[2] < self.Max[2]
+}
+
+// Contains returns if all cells of other are inside the box.
+func (self *Box) Contains(other *Box) bool {
+	return other.Min[0] >= self.Min[0] && other.Max[0] <= self.Max[0] &&
+		other.Min[1] >= self.Min[1] && other.Max[1] <= self.Max[1] &&
+		other.Min[2] >= self.Min[2] && other.Max[2] <= self.Max[2]
+}
+
+// Intersects returns if the boxes share at least one cell.
+func (self *Box) Intersects(other *Box) bool {
+	return self.Min[0] < other.Max[0] && other.Min[0] < self.Max[0] &&
+		self.Min[1] < other.Max[1] && other.Min[1] < self.Max[1] &&
+		self.Min[2] < other.Max[2] && other.Min[2] < self.Max[2]
+}
+
+// ExtendByPoint grows the box to contain the cell p.
+func (self *Box) ExtendByPoint(p *T) *Box {
+	if self.IsEmpty() {
+		self.Min = *p
+		self.Max = T{p[0] + 1, p[1] + 1, p[2] + 1}
+		return self
+	}
+	end := T{p[0] + 1, p[1] + 1, p[2] + 1}
+	self.Min = Min(&self.Min, p)
+	self.Max = Max(&self.Max, &end)
+	return self
+}
+
+// Vec3Box returns the space covered by the cells as vec3.Box.
+func (self *Box) Vec3Box() vec3.Box {
+	return vec3.Box{Min: self.Min.Vec3(), Max: self.Max.Vec3()}
+}
+
+// ForEachCell calls f for every cell of the box with X varying fastest
+// until f returns false.
+func (self *Box) ForEachCell(f func(cell T) bool) {
+	if self.IsEmpty() {
+		return
+	}
+	var c T
+	for c[2] = self.Min[2]; c[2] < self.Max[2]; c[2]++ {
+		for c[1] = self.Min[1]; c[1] < self.Max[1]; c[1]++ {
+			for c[0] = self.Min[0]; c[0] < self.Max[0]; c[0]++ {
+				if !f(c) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Intersect returns the cells shared by a and b.
+func Intersect(a, b *Box) Box {
+	return Box{Max(&a.Min, &b.Min), Min(&a.Max, &b.Max)}
+}
+
+// Join returns the smallest box containing a and b.
+func Join(a, b *Box) Box {
+	if a.IsEmpty() {
+		return *b
+	}
+	if b.IsEmpty() {
+		return *a
+	}
+	return Box{Min(&a.Min, &b.Min), Max(&a.Max, &b.Max)}
+}
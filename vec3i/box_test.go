@@ -0,0 +1,106 @@
+package vec3i
+
+import (
+	"testing"
+
+	"github.com/ungerik/go3d/vec3"
+)
+
+func TestBoxCovering(t *testing.T) {
+	tests := []struct {
+		box  vec3.Box
+		want Box
+	}{
+		{vec3.Box{Min: vec3.T{0.5, 0.5, 0.5}, Max: vec3.T{0.7, 0.7, 0.7}}, Box{T{0, 0, 0}, T{1, 1, 1}}},
+		{vec3.Box{Min: vec3.T{-0.5, -1.5, -2.5}, Max: vec3.T{0.5, -0.5, -2.2}}, Box{T{-1, -2, -3}, T{1, 0, -2}}},
+		{vec3.Box{Min: vec3.T{-3.2, 1.1, -0.1}, Max: vec3.T{-1.9, 3.9, 0.1}}, Box{T{-4, 1, -1}, T{-1, 4, 1}}},
+		// Boxes are closed, so a box ending on a cell border touches the next cell.
+		{vec3.Box{Min: vec3.T{-1, 0, 1}, Max: vec3.T{1, 2, 3}}, Box{T{-1, 0, 1}, T{2, 3, 4}}},
+	}
+	for _, test := range tests {
+		b := BoxCovering(&test.box)
+		if b != test.want {
+			t.Errorf("BoxCovering(%v) = %v, want %v", test.box, b, test.want)
+		}
+		// The covering box contains the corners of the box.
+		for _, corner := range test.box.Corners() {
+			if c := Floor(&corner); !b.ContainsPoint(&c) {
+				t.Errorf("BoxCovering(%v) = %v does not contain the cell %v", test.box, b, c)
+			}
+		}
+	}
+}
+
+func TestBoxCells(t *testing.T) {
+	b := Box{T{-1, -2, 0}, T{1, 1, 2}}
+	if v := b.Volume(); v != 12 {
+		t.Errorf("Volume of %v = %d, want 12", b, v)
+	}
+	if s := b.Size(); s != (T{2, 3, 2}) {
+		t.Errorf("Size of %v = %v, want 2 3 2", b, s)
+	}
+	for _, p := range []T{b.Min, {0, 0, 1}, {0, -2, 1}} {
+		if !b.ContainsPoint(&p) {
+			t.Errorf("%v does not contain %v", b, p)
+		}
+	}
+	// Max is excluded.
+	for _, p := range []T{b.Max, {1, 0, 0}, {0, 1, 0}, {0, 0, 2}, {-2, 0, 0}} {
+		if b.ContainsPoint(&p) {
+			t.Errorf("%v contains %v", b, p)
+		}
+	}
+	empty := Box{T{1, 1, 1}, T{1, 5, 5}}
+	if !empty.IsEmpty() || empty.Volume() != 0 || empty.ContainsPoint(&empty.Min) {
+		t.Errorf("%v is not empty", empty)
+	}
+	inverted := Box{T{1, 1, 1}, T{0, 5, 5}}
+	if inverted.Volume() != 0 {
+		t.Errorf("Volume of %v = %d, want 0", inverted, inverted.Volume())
+	}
+
+	var cells []T
+	b.ForEachCell(func(cell T) bool {
+		if !b.ContainsPoint(&cell) {
+			t.Errorf("ForEachCell of %v visited %v", b, cell)
+		}
+		cells = append(cells, cell)
+		return true
+	})
+	if len(cells) != b.Volume() {
+		t.Fatalf("ForEachCell of %v visited %d cells, want %d", b, len(cells), b.Volume())
+	}
+	// X varies fastest, then Y and Z.
+	for i := 1; i < len(cells); i++ {
+		prev, cell := cells[i-1], cells[i]
+		if cell[2] < prev[2] || cell[2] == prev[2] && (cell[1] < prev[1] || cell[1] == prev[1] && cell[0] <= prev[0]) {
+			t.Fatalf("ForEachCell of %v visited %v after %v", b, cell, prev)
+		}
+	}
+	if cells[0] != b.Min || cells[1] != (T{0, -2, 0}) || cells[2] != (T{-1, -1, 0}) {
+		t.Errorf("ForEachCell of %v started with %v", b, cells[:3])
+	}
+
+	count := 0
+	b.ForEachCell(func(cell T) bool {
+		count++
+		return count < 5
+	})
+	if count != 5 {
+		t.Errorf("ForEachCell continued after f returned false and visited %d cells", count)
+	}
+	empty.ForEachCell(func(cell T) bool {
+		t.Errorf("ForEachCell of the empty %v visited %v", empty, cell)
+		return true
+	})
+}
+
+func TestParseBox(t *testing.T) {
+	for _, b := range []Box{{}, {T{-1, -2, -3}, T{4, 5, 6}}, {MinVal, MaxVal}} {
+		s := b.String()
+		p, err := ParseBox(s)
+		if err != nil || p != b {
+			t.Errorf("ParseBox(%q) = %v, %v, want %v", s, p, err, b)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package vec3i
+
+import (
+	"testing"
+
+	"github.com/ungerik/go3d/vec3"
+)
+
+func TestRounding(t *testing.T) {
+	tests := []struct {
+		v                  vec3.T
+		floor, round, ceil T
+	}{
+		{vec3.T{0, 0, 0}, T{0, 0, 0}, T{0, 0, 0}, T{0, 0, 0}},
+		{vec3.T{1.2, -1.2, -0.2}, T{1, -2, -1}, T{1, -1, 0}, T{2, -1, 0}},
+		{vec3.T{0.5, -0.5, -1.5}, T{0, -1, -2}, T{1, -1, -2}, T{1, 0, -1}},
+		{vec3.T{2.5, -2.5, -2.7}, T{2, -3, -3}, T{3, -3, -3}, T{3, -2, -2}},
+		{vec3.T{-3, 7, -1e6}, T{-3, 7, -1e6}, T{-3, 7, -1e6}, T{-3, 7, -1e6}},
+	}
+	for _, test := range tests {
+		if r := Floor(&test.v); r != test.floor {
+			t.Errorf("Floor(%v) = %v, want %v", test.v, r, test.floor)
+		}
+		if r := Round(&test.v); r != test.round {
+			t.Errorf("Round(%v) = %v, want %v", test.v, r, test.round)
+		}
+		if r := Ceil(&test.v); r != test.ceil {
+			t.Errorf("Ceil(%v) = %v, want %v", test.v, r, test.ceil)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, v := range []T{Zero, UnitZ, {-1, 2, -3}, MinVal, MaxVal} {
+		s := v.String()
+		p, err := Parse(s)
+		if err != nil || p != v {
+			t.Errorf("Parse(%q) = %v, %v, want %v", s, p, err, v)
+		}
+	}
+	if _, err := Parse("1 x 3"); err == nil {
+		t.Error("Parse of an invalid string returned no error")
+	}
+}
+
+func TestDistances(t *testing.T) {
+	tests := []struct {
+		a, b                 T
+		manhattan, chebyshev int32
+	}{
+		{T{0, 0, 0}, T{0, 0, 0}, 0, 0},
+		{T{1, 2, 3}, T{1, 2, 3}, 0, 0},
+		{T{0, 0, 0}, T{3, -4, 5}, 12, 5},
+		{T{-2, -2, -2}, T{1, 2, -3}, 8, 4},
+		{T{5, 0, -7}, T{-5, 0, 0}, 17, 10},
+	}
+	for _, test := range tests {
+		for _, ab := range [][2]*T{{&test.a, &test.b}, {&test.b, &test.a}} {
+			if d := ManhattanDistance(ab[0], ab[1]); d != test.manhattan {
+				t.Errorf("ManhattanDistance(%v, %v) = %d, want %d", ab[0], ab[1], d, test.manhattan)
+			}
+			if d := ChebyshevDistance(ab[0], ab[1]); d != test.chebyshev {
+				t.Errorf("ChebyshevDistance(%v, %v) = %d, want %d", ab[0], ab[1], d, test.chebyshev)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package vecn
+
+import (
+	"unsafe"
+
+	"github.com/ungerik/go3d/mat4x4"
+	"github.com/ungerik/go3d/mat4x4d"
+	"github.com/ungerik/go3d/quaternion"
+	"github.com/ungerik/go3d/quaterniond"
+	"github.com/ungerik/go3d/vec3"
+	"github.com/ungerik/go3d/vec3d"
+	"github.com/ungerik/go3d/vec4"
+	"github.com/ungerik/go3d/vec4d"
+)
+
+// The conversion functions return pointers to the same memory as their
+// arguments, so modifications through the result modify the argument.
+// Values can be converted by dereferencing the result, like *FromVec3(&v).
+
+func FromVec3(v *vec3.T) *Vec3[float32] {
+	return (*Vec3[float32])(v)
+}
+
+func FromVec3d(v *vec3d.T) *Vec3[float64] {
+	return (*Vec3[float64])(v)
+}
+
+func ToVec3(v *Vec3[float32]) *vec3.T {
+	return (*vec3.T)(v)
+}
+
+func ToVec3d(v *Vec3[float64]) *vec3d.T {
+	return (*vec3d.T)(v)
+}
+
+func FromVec4(v *vec4.T) *Vec4[float32] {
+	return (*Vec4[float32])(v)
+}
+
+func FromVec4d(v *vec4d.T) *Vec4[float64] {
+	return (*Vec4[float64])(v)
+}
+
+func ToVec4(v *Vec4[float32]) *vec4.T {
+	return (*vec4.T)(v)
+}
+
+func ToVec4d(v *Vec4[float64]) *vec4d.T {
+	return (*vec4d.T)(v)
+}
+
+// FromMat4x4 has to use unsafe because the column types of the matrices differ,
+// the memory layout is the same.
+func FromMat4x4(m *mat4x4.T) *Mat4[float32] {
+	return (*Mat4[float32])(unsafe.Pointer(m))
+}
+
+func FromMat4x4d(m *mat4x4d.T) *Mat4[float64] {
+	return (*Mat4[float64])(unsafe.Pointer(m))
+}
+
+func ToMat4x4(m *Mat4[float32]) *mat4x4.T {
+	return (*mat4x4.T)(unsafe.Pointer(m))
+}
+
+func ToMat4x4d(m *Mat4[float64]) *mat4x4d.T {
+	return (*mat4x4d.T)(unsafe.Pointer(m))
+}
+
+func FromQuaternion(q *quaternion.T) *Quat[float32] {
+	return (*Quat[float32])(q)
+}
+
+func FromQuaterniond(q *quaterniond.T) *Quat[float64] {
+	return (*Quat[float64])(q)
+}
+
+func ToQuaternion(q *Quat[float32]) *quaternion.T {
+	return (*quaternion.T)(q)
+}
+
+func ToQuaterniond(q *Quat[float64]) *quaterniond.T {
+	return (*quaterniond.T)(q)
+}
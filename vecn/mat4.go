@@ -0,0 +1,239 @@
+package vecn
+
+import (
+	"fmt"
+)
+
+// Mat4 is a generic 4x4 matrix stored column by column like mat4x4.T.
+type Mat4[F Float] [4]Vec4[F]
+
+// IdentMat4 returns the identity matrix.
+func IdentMat4[F Float]() Mat4[F] {
+	return Mat4[F]{
+		Vec4[F]{1, 0, 0, 0},
+		Vec4[F]{0, 1, 0, 0},
+		Vec4[F]{0, 0, 1, 0},
+		Vec4[F]{0, 0, 0, 1},
+	}
+}
+
+// String formats Mat4 as string. See also ParseMat4().
+func (self *Mat4[F]) String() string {
+	return fmt.Sprintf("%s %s %s %s", self[0].String(), self[1].String(), self[2].String(), self[3].String())
+}
+
+// ParseMat4 parses Mat4 from a string. See also String()
+func ParseMat4[F Float](s string) (r Mat4[F], err error) {
+	_, err = fmt.Sscan(s,
+		&r[0][0], &r[0][1], &r[0][2], &r[0][3],
+		&r[1][0], &r[1][1], &r[1][2], &r[1][3],
+		&r[2][0], &r[2][1], &r[2][2], &r[2][3],
+		&r[3][0], &r[3][1], &r[3][2], &r[3][3],
+	)
+	return r, err
+}
+
+// IsZero checks if all elements of the matrix are zero.
+func (self *Mat4[F]) IsZero() bool {
+	return self[0].IsZero() && self[1].IsZero() && self[2].IsZero() && self[3].IsZero()
+}
+
+// Scale multiplies the diagonal scale elements by f returns self.
+func (self *Mat4[F]) Scale(f F) *Mat4[F] {
+	self[0][0] *= f
+	self[1][1] *= f
+	self[2][2] *= f
+	return self
+}
+
+func (self *Mat4[F]) Trace() F {
+	return self[0][0] + self[1][1] + self[2][2] + self[3][3]
+}
+
+func (self *Mat4[F]) AssignMul(a, b *Mat4[F]) *Mat4[F] {
+	self[0] = a.MulVec4(&b[0])
+	self[1] = a.MulVec4(&b[1])
+	self[2] = a.MulVec4(&b[2])
+	self[3] = a.MulVec4(&b[3])
+	return self
+}
+
+func (self *Mat4[F]) MulVec4(vec *Vec4[F]) Vec4[F] {
+	return Vec4[F]{
+		self[0][0]*vec[0] + self[1][0]*vec[1] + self[2][0]*vec[2] + self[3][0]*vec[3],
+		self[0][1]*vec[0] + self[1][1]*vec[1] + self[2][1]*vec[2] + self[3][1]*vec[3],
+		self[0][2]*vec[0] + self[1][2]*vec[1] + self[2][2]*vec[2] + self[3][2]*vec[3],
+		self[0][3]*vec[0] + self[1][3]*vec[1] + self[2][3]*vec[2] + self[3][3]*vec[3],
+	}
+}
+
+// MulVec3 transforms v as point and divides the result by w.
+func (self *Mat4[F]) MulVec3(v *Vec3[F]) Vec3[F] {
+	v4 := v.Vec4()
+	v4 = self.MulVec4(&v4)
+	return v4.Vec3DividedByW()
+}
+
+func (self *Mat4[F]) SetTranslation(v *Vec3[F]) *Mat4[F] {
+	self[3][0] = v[0]
+	self[3][1] = v[1]
+	self[3][2] = v[2]
+	return self
+}
+
+func (self *Mat4[F]) Translate(v *Vec3[F]) *Mat4[F] {
+	self[3][0] += v[0]
+	self[3][1] += v[1]
+	self[3][2] += v[2]
+	return self
+}
+
+func (self *Mat4[F]) ScaleVec3(s *Vec3[F]) *Mat4[F] {
+	self[0][0] *= s[0]
+	self[1][1] *= s[1]
+	self[2][2] *= s[2]
+	return self
+}
+
+func (self *Mat4[F]) AssignQuaternion(q *Quat[F]) *Mat4[F] {
+	xx := q[0] * q[0] * 2
+	yy := q[1] * q[1] * 2
+	zz := q[2] * q[2] * 2
+	xy := q[0] * q[1] * 2
+	xz := q[0] * q[2] * 2
+	yz := q[1] * q[2] * 2
+	wx := q[3] * q[0] * 2
+	wy := q[3] * q[1] * 2
+	wz := q[3] * q[2] * 2
+
+	*self = Mat4[F]{
+		Vec4[F]{1 - (yy + zz), xy + wz, xz - wy, 0},
+		Vec4[F]{xy - wz, 1 - (xx + zz), yz + wx, 0},
+		Vec4[F]{xz + wy, yz - wx, 1 - (xx + yy), 0},
+		Vec4[F]{0, 0, 0, 1},
+	}
+	return self
+}
+
+func (self *Mat4[F]) AssignXRotation(angle F) *Mat4[F] {
+	c, s := cos(angle), sin(angle)
+	*self = Mat4[F]{
+		Vec4[F]{1, 0, 0, 0},
+		Vec4[F]{0, c, s, 0},
+		Vec4[F]{0, -s, c, 0},
+		Vec4[F]{0, 0, 0, 1},
+	}
+	return self
+}
+
+func (self *Mat4[F]) AssignYRotation(angle F) *Mat4[F] {
+	c, s := cos(angle), sin(angle)
+	*self = Mat4[F]{
+		Vec4[F]{c, 0, -s, 0},
+		Vec4[F]{0, 1, 0, 0},
+		Vec4[F]{s, 0, c, 0},
+		Vec4[F]{0, 0, 0, 1},
+	}
+	return self
+}
+
+func (self *Mat4[F]) AssignZRotation(angle F) *Mat4[F] {
+	c, s := cos(angle), sin(angle)
+	*self = Mat4[F]{
+		Vec4[F]{c, s, 0, 0},
+		Vec4[F]{-s, c, 0, 0},
+		Vec4[F]{0, 0, 1, 0},
+		Vec4[F]{0, 0, 0, 1},
+	}
+	return self
+}
+
+func (self *Mat4[F]) Transpose() *Mat4[F] {
+	for col := 0; col < 4; col++ {
+		for row := col + 1; row < 4; row++ {
+			self[col][row], self[row][col] = self[row][col], self[col][row]
+		}
+	}
+	return self
+}
+
+func (self *Mat4[F]) Determinant3x3() F {
+	return self[0][0]*self[1][1]*self[2][2] +
+		self[1][0]*self[2][1]*self[0][2] +
+		self[2][0]*self[0][1]*self[1][2] -
+		self[2][0]*self[1][1]*self[0][2] -
+		self[1][0]*self[0][1]*self[2][2] -
+		self[0][0]*self[2][1]*self[1][2]
+}
+
+// Determinant returns the determinant of the full 4x4 matrix.
+func (self *Mat4[F]) Determinant() F {
+	s, c := self.subDeterminants()
+	return s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+}
+
+func (self *Mat4[F]) subDeterminants() (s, c [6]F) {
+	m := self
+	s[0] = m[0][0]*m[1][1] - m[1][0]*m[0][1]
+	s[1] = m[0][0]*m[1][2] - m[1][0]*m[0][2]
+	s[2] = m[0][0]*m[1][3] - m[1][0]*m[0][3]
+	s[3] = m[0][1]*m[1][2] - m[1][1]*m[0][2]
+	s[4] = m[0][1]*m[1][3] - m[1][1]*m[0][3]
+	s[5] = m[0][2]*m[1][3] - m[1][2]*m[0][3]
+
+	c[0] = m[2][0]*m[3][1] - m[3][0]*m[2][1]
+	c[1] = m[2][0]*m[3][2] - m[3][0]*m[2][2]
+	c[2] = m[2][0]*m[3][3] - m[3][0]*m[2][3]
+	c[3] = m[2][1]*m[3][2] - m[3][1]*m[2][2]
+	c[4] = m[2][1]*m[3][3] - m[3][1]*m[2][3]
+	c[5] = m[2][2]*m[3][3] - m[3][2]*m[2][3]
+	return s, c
+}
+
+// Invert inverts the matrix.
+// If the matrix is singular ErrSingular is returned and the matrix is not modified.
+func (self *Mat4[F]) Invert() error {
+	m := self
+	s, c := self.subDeterminants()
+	det := s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+	if det == 0 {
+		return ErrSingular
+	}
+	ooDet := 1 / det
+
+	*self = Mat4[F]{
+		Vec4[F]{
+			(m[1][1]*c[5] - m[1][2]*c[4] + m[1][3]*c[3]) * ooDet,
+			(-m[0][1]*c[5] + m[0][2]*c[4] - m[0][3]*c[3]) * ooDet,
+			(m[3][1]*s[5] - m[3][2]*s[4] + m[3][3]*s[3]) * ooDet,
+			(-m[2][1]*s[5] + m[2][2]*s[4] - m[2][3]*s[3]) * ooDet,
+		},
+		Vec4[F]{
+			(-m[1][0]*c[5] + m[1][2]*c[2] - m[1][3]*c[1]) * ooDet,
+			(m[0][0]*c[5] - m[0][2]*c[2] + m[0][3]*c[1]) * ooDet,
+			(-m[3][0]*s[5] + m[3][2]*s[2] - m[3][3]*s[1]) * ooDet,
+			(m[2][0]*s[5] - m[2][2]*s[2] + m[2][3]*s[1]) * ooDet,
+		},
+		Vec4[F]{
+			(m[1][0]*c[4] - m[1][1]*c[2] + m[1][3]*c[0]) * ooDet,
+			(-m[0][0]*c[4] + m[0][1]*c[2] - m[0][3]*c[0]) * ooDet,
+			(m[3][0]*s[4] - m[3][1]*s[2] + m[3][3]*s[0]) * ooDet,
+			(-m[2][0]*s[4] + m[2][1]*s[2] - m[2][3]*s[0]) * ooDet,
+		},
+		Vec4[F]{
+			(-m[1][0]*c[3] + m[1][1]*c[1] - m[1][2]*c[0]) * ooDet,
+			(m[0][0]*c[3] - m[0][1]*c[1] + m[0][2]*c[0]) * ooDet,
+			(-m[3][0]*s[3] + m[3][1]*s[1] - m[3][2]*s[0]) * ooDet,
+			(m[2][0]*s[3] - m[2][1]*s[1] + m[2][2]*s[0]) * ooDet,
+		},
+	}
+	return nil
+}
+
+// Inverted returns an inverted copy of the matrix.
+// If the matrix is singular ErrSingular is returned.
+func (self *Mat4[F]) Inverted() (Mat4[F], error) {
+	r := *self
+	err := r.Invert()
+	return r, err
+}
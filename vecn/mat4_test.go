@@ -0,0 +1,182 @@
+//go:build go1.18
+// +build go1.18
+
+package vecn
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ungerik/go3d/mat4x4"
+	"github.com/ungerik/go3d/mat4x4d"
+)
+
+func TestMat4Float32(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		a, b := randomMat4[float32](r), randomMat4[float32](r)
+		ca, cb := ToMat4x4(&a), ToMat4x4(&b)
+
+		var got Mat4[float32]
+		var want mat4x4.T
+		got.AssignMul(&a, &b)
+		want.AssignMul(ca, cb)
+		if !nearMat4(&got, FromMat4x4(&want), epsilon32) {
+			t.Fatalf("AssignMul(%v, %v) = %v, want %v", a, b, got, want)
+		}
+
+		v := randomVec3[float32](r)
+		v4 := v.Vec4()
+		if got, want := a.MulVec4(&v4), ca.MulVec4(ToVec4(&v4)); !nearSlice(got[:], want[:], epsilon32) {
+			t.Fatalf("MulVec4(%v) = %v, want %v", v4, got, want)
+		}
+		if got, want := a.MulVec3(&v), ca.MulVec3(ToVec3(&v)); !nearSlice(got[:], want[:], epsilon32) {
+			t.Fatalf("MulVec3(%v) = %v, want %v", v, got, want)
+		}
+		if got, want := a.Determinant(), ca.Determinant(); !near(got, want, epsilon32) {
+			t.Fatalf("Determinant of %v = %f, want %f", a, got, want)
+		}
+		if got, want := a.Determinant3x3(), ca.Determinant3x3(); !near(got, want, epsilon32) {
+			t.Fatalf("Determinant3x3 of %v = %f, want %f", a, got, want)
+		}
+		if got, want := a.Trace(), ca.Trace(); !near(got, want, epsilon32) {
+			t.Fatalf("Trace of %v = %f, want %f", a, got, want)
+		}
+
+		got, want = a, *ca
+		got.Transpose()
+		want.Transpose()
+		if !nearMat4(&got, FromMat4x4(&want), 0) {
+			t.Fatalf("Transpose of %v = %v, want %v", a, got, want)
+		}
+
+		inverse, err := a.Inverted()
+		wantInverse, wantErr := ca.Inverted()
+		if err != nil || wantErr != nil {
+			t.Fatalf("Inverted of %v returned %v, want %v", a, err, wantErr)
+		}
+		if !nearMat4(&inverse, FromMat4x4(&wantInverse), epsilon32*100) {
+			t.Fatalf("Inverted of %v = %v, want %v", a, inverse, wantInverse)
+		}
+
+		q := randomQuat[float32](r)
+		got.AssignQuaternion(&q)
+		want.AssignQuaternion(ToQuaternion(&q))
+		if !nearMat4(&got, FromMat4x4(&want), epsilon32) {
+			t.Fatalf("AssignQuaternion(%v) = %v, want %v", q, got, want)
+		}
+
+		angle := random[float32](r, -3, 3)
+		operations := []struct {
+			name string
+			got  func(m *Mat4[float32])
+			want func(m *mat4x4.T)
+		}{
+			{"AssignXRotation", func(m *Mat4[float32]) { m.AssignXRotation(angle) }, func(m *mat4x4.T) { m.AssignXRotation(angle) }},
+			{"AssignYRotation", func(m *Mat4[float32]) { m.AssignYRotation(angle) }, func(m *mat4x4.T) { m.AssignYRotation(angle) }},
+			{"AssignZRotation", func(m *Mat4[float32]) { m.AssignZRotation(angle) }, func(m *mat4x4.T) { m.AssignZRotation(angle) }},
+			{"Translate", func(m *Mat4[float32]) { m.Translate(&v) }, func(m *mat4x4.T) { m.Translate(ToVec3(&v)) }},
+			{"SetTranslation", func(m *Mat4[float32]) { m.SetTranslation(&v) }, func(m *mat4x4.T) { m.SetTranslation(ToVec3(&v)) }},
+			{"ScaleVec3", func(m *Mat4[float32]) { m.ScaleVec3(&v) }, func(m *mat4x4.T) { m.ScaleVec3(ToVec3(&v)) }},
+			{"Scale", func(m *Mat4[float32]) { m.Scale(angle) }, func(m *mat4x4.T) { m.Scale(angle) }},
+		}
+		for _, op := range operations {
+			got, want = a, *ca
+			op.got(&got)
+			op.want(&want)
+			if !nearMat4(&got, FromMat4x4(&want), epsilon32) {
+				t.Fatalf("%s of %v = %v, want %v", op.name, a, got, want)
+			}
+		}
+	}
+
+	var singular Mat4[float32]
+	if _, err := singular.Inverted(); err != ErrSingular {
+		t.Errorf("Inverted of a zero matrix returned %v, want ErrSingular", err)
+	}
+}
+
+func TestMat4Float64(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for n := 0; n < 200; n++ {
+		a, b := randomMat4[float64](r), randomMat4[float64](r)
+		ca, cb := ToMat4x4d(&a), ToMat4x4d(&b)
+
+		var got Mat4[float64]
+		var want mat4x4d.T
+		got.AssignMul(&a, &b)
+		want.AssignMul(ca, cb)
+		if !nearMat4(&got, FromMat4x4d(&want), epsilon64) {
+			t.Fatalf("AssignMul(%v, %v) = %v, want %v", a, b, got, want)
+		}
+
+		v := randomVec3[float64](r)
+		v4 := v.Vec4()
+		if got, want := a.MulVec4(&v4), ca.MulVec4(ToVec4d(&v4)); !nearSlice(got[:], want[:], epsilon64) {
+			t.Fatalf("MulVec4(%v) = %v, want %v", v4, got, want)
+		}
+		if got, want := a.MulVec3(&v), ca.MulVec3(ToVec3d(&v)); !nearSlice(got[:], want[:], epsilon64) {
+			t.Fatalf("MulVec3(%v) = %v, want %v", v, got, want)
+		}
+		if got, want := a.Determinant(), ca.Determinant(); !near(got, want, epsilon64) {
+			t.Fatalf("Determinant of %v = %f, want %f", a, got, want)
+		}
+		if got, want := a.Determinant3x3(), ca.Determinant3x3(); !near(got, want, epsilon64) {
+			t.Fatalf("Determinant3x3 of %v = %f, want %f", a, got, want)
+		}
+		if got, want := a.Trace(), ca.Trace(); !near(got, want, epsilon64) {
+			t.Fatalf("Trace of %v = %f, want %f", a, got, want)
+		}
+
+		got, want = a, *ca
+		got.Transpose()
+		want.Transpose()
+		if !nearMat4(&got, FromMat4x4d(&want), 0) {
+			t.Fatalf("Transpose of %v = %v, want %v", a, got, want)
+		}
+
+		inverse, err := a.Inverted()
+		wantInverse, wantErr := ca.Inverted()
+		if err != nil || wantErr != nil {
+			t.Fatalf("Inverted of %v returned %v, want %v", a, err, wantErr)
+		}
+		if !nearMat4(&inverse, FromMat4x4d(&wantInverse), epsilon64*100) {
+			t.Fatalf("Inverted of %v = %v, want %v", a, inverse, wantInverse)
+		}
+
+		q := randomQuat[float64](r)
+		got.AssignQuaternion(&q)
+		want.AssignQuaternion(ToQuaterniond(&q))
+		if !nearMat4(&got, FromMat4x4d(&want), epsilon64) {
+			t.Fatalf("AssignQuaternion(%v) = %v, want %v", q, got, want)
+		}
+
+		angle := random[float64](r, -3, 3)
+		operations := []struct {
+			name string
+			got  func(m *Mat4[float64])
+			want func(m *mat4x4d.T)
+		}{
+			{"AssignXRotation", func(m *Mat4[float64]) { m.AssignXRotation(angle) }, func(m *mat4x4d.T) { m.AssignXRotation(angle) }},
+			{"AssignYRotation", func(m *Mat4[float64]) { m.AssignYRotation(angle) }, func(m *mat4x4d.T) { m.AssignYRotation(angle) }},
+			{"AssignZRotation", func(m *Mat4[float64]) { m.AssignZRotation(angle) }, func(m *mat4x4d.T) { m.AssignZRotation(angle) }},
+			{"Translate", func(m *Mat4[float64]) { m.Translate(&v) }, func(m *mat4x4d.T) { m.Translate(ToVec3d(&v)) }},
+			{"SetTranslation", func(m *Mat4[float64]) { m.SetTranslation(&v) }, func(m *mat4x4d.T) { m.SetTranslation(ToVec3d(&v)) }},
+			{"ScaleVec3", func(m *Mat4[float64]) { m.ScaleVec3(&v) }, func(m *mat4x4d.T) { m.ScaleVec3(ToVec3d(&v)) }},
+			{"Scale", func(m *Mat4[float64]) { m.Scale(angle) }, func(m *mat4x4d.T) { m.Scale(angle) }},
+		}
+		for _, op := range operations {
+			got, want = a, *ca
+			op.got(&got)
+			op.want(&want)
+			if !nearMat4(&got, FromMat4x4d(&want), epsilon64) {
+				t.Fatalf("%s of %v = %v, want %v", op.name, a, got, want)
+			}
+		}
+	}
+
+	var singular Mat4[float64]
+	if _, err := singular.Inverted(); err != ErrSingular {
+		t.Errorf("Inverted of a zero matrix returned %v, want ErrSingular", err)
+	}
+}
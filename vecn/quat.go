@@ -0,0 +1,150 @@
+package vecn
+
+import (
+	"fmt"
+)
+
+// Quat is a generic quaternion with the elements x, y, z, w like quaternion.T.
+type Quat[F Float] [4]F
+
+// IdentQuat returns the identity quaternion.
+func IdentQuat[F Float]() Quat[F] {
+	return Quat[F]{0, 0, 0, 1}
+}
+
+// QuatFromAxisAngle returns the rotation of angle radians around axis.
+func QuatFromAxisAngle[F Float](axis *Vec3[F], angle F) Quat[F] {
+	angle *= 0.5
+	s := sin(angle)
+	q := Quat[F]{axis[0] * s, axis[1] * s, axis[2] * s, cos(angle)}
+	return q.Normalized()
+}
+
+// String formats Quat as string. See also ParseQuat().
+func (self *Quat[F]) String() string {
+	return fmt.Sprintf("%f %f %f %f", self[0], self[1], self[2], self[3])
+}
+
+// ParseQuat parses Quat from a string. See also String()
+func ParseQuat[F Float](s string) (r Quat[F], err error) {
+	_, err = fmt.Sscanf(s, "%f %f %f %f", &r[0], &r[1], &r[2], &r[3])
+	return r, err
+}
+
+func (self *Quat[F]) AxisAngle() (axis Vec3[F], angle F) {
+	c := self[3]
+	s := sqrt(1 - c*c)
+	angle = acos(c)
+
+	var ooSin F
+	if abs(s) < 0.0005 {
+		ooSin = 1
+	} else {
+		ooSin = 1 / s
+	}
+	axis[0] = self[0] * ooSin
+	axis[1] = self[1] * ooSin
+	axis[2] = self[2] * ooSin
+
+	return axis, angle
+}
+
+func (self *Quat[F]) Norm() F {
+	return self[0]*self[0] + self[1]*self[1] + self[2]*self[2] + self[3]*self[3]
+}
+
+func (self *Quat[F]) Normalize() *Quat[F] {
+	norm := self.Norm()
+	if norm != 1 && norm != 0 {
+		ool := 1 / sqrt(norm)
+		self[0] *= ool
+		self[1] *= ool
+		self[2] *= ool
+		self[3] *= ool
+	}
+	return self
+}
+
+func (self *Quat[F]) Normalized() Quat[F] {
+	q := *self
+	q.Normalize()
+	return q
+}
+
+func (self *Quat[F]) Negate() *Quat[F] {
+	self[0] = -self[0]
+	self[1] = -self[1]
+	self[2] = -self[2]
+	self[3] = -self[3]
+	return self
+}
+
+func (self *Quat[F]) Negated() Quat[F] {
+	return Quat[F]{-self[0], -self[1], -self[2], -self[3]}
+}
+
+func (self *Quat[F]) Invert() *Quat[F] {
+	self[0] = -self[0]
+	self[1] = -self[1]
+	self[2] = -self[2]
+	return self
+}
+
+func (self *Quat[F]) Inverted() Quat[F] {
+	return Quat[F]{-self[0], -self[1], -self[2], self[3]}
+}
+
+func (self *Quat[F]) IsUnitQuat(tolerance F) bool {
+	norm := self.Norm()
+	return norm >= (1.0-tolerance) && norm <= (1.0+tolerance)
+}
+
+// RotateVec3 rotates v by the quaternion. See also RotatedVec3.
+func (self *Quat[F]) RotateVec3(v *Vec3[F]) {
+	*v = self.RotatedVec3(v)
+}
+
+// RotatedVec3 returns v rotated by the quaternion.
+// Unlike quaternion.T.RotatedVec3, which normalizes the intermediate
+// products with quaternion.Mul and so always returns a unit vector,
+// the length of v is kept for unit quaternions.
+// A quaternion with the norm n additionally scales v by n*n.
+func (self *Quat[F]) RotatedVec3(v *Vec3[F]) Vec3[F] {
+	qv := Quat[F]{v[0], v[1], v[2], 0}
+	inv := self.Inverted()
+	q := MulQuat(self, &qv)
+	q = MulQuat(&q, &inv)
+	return Vec3[F]{q[0], q[1], q[2]}
+}
+
+func DotQuat[F Float](a, b *Quat[F]) F {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] + a[3]*b[3]
+}
+
+// MulQuat returns the product of a and b,
+// which is the rotation b followed by the rotation a.
+// Unlike quaternion.Mul the result is not normalized.
+func MulQuat[F Float](a, b *Quat[F]) Quat[F] {
+	return Quat[F]{
+		a[3]*b[0] + a[0]*b[3] + a[1]*b[2] - a[2]*b[1],
+		a[3]*b[1] + a[1]*b[3] + a[2]*b[0] - a[0]*b[2],
+		a[3]*b[2] + a[2]*b[3] + a[0]*b[1] - a[1]*b[0],
+		a[3]*b[3] - a[0]*b[0] - a[1]*b[1] - a[2]*b[2],
+	}
+}
+
+func Slerp[F Float](a, b *Quat[F], f F) Quat[F] {
+	d := acos(DotQuat(a, b))
+	ooSinD := 1 / sin(d)
+
+	f1 := sin(d*(1-f)) * ooSinD
+	f2 := sin(d*f) * ooSinD
+
+	q := Quat[F]{
+		a[0]*f1 + b[0]*f2,
+		a[1]*f1 + b[1]*f2,
+		a[2]*f1 + b[2]*f2,
+		a[3]*f1 + b[3]*f2,
+	}
+	return q.Normalized()
+}
@@ -0,0 +1,91 @@
+//go:build go1.18
+// +build go1.18
+
+package vecn
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ungerik/go3d/mat4x4"
+	"github.com/ungerik/go3d/mat4x4d"
+	"github.com/ungerik/go3d/quaternion"
+	"github.com/ungerik/go3d/quaterniond"
+)
+
+func TestQuatFloat32(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for n := 0; n < 200; n++ {
+		axis := randomVec3[float32](r)
+		axis.Normalize()
+		angle := random[float32](r, -3, 3)
+		a := QuatFromAxisAngle(&axis, angle)
+		if want := quaternion.FromAxisAngle(ToVec3(&axis), angle); !nearSlice(a[:], want[:], epsilon32) {
+			t.Fatalf("QuatFromAxisAngle(%v, %f) = %v, want %v", axis, angle, a, want)
+		}
+		b := randomQuat[float32](r)
+		ca, cb := ToQuaternion(&a), ToQuaternion(&b)
+
+		if got, want := MulQuat(&a, &b), quaternion.Mul(ca, cb); !nearSlice(got[:], want[:], epsilon32) {
+			t.Fatalf("MulQuat(%v, %v) = %v, want %v", a, b, got, want)
+		}
+		if got, want := DotQuat(&a, &b), quaternion.Dot(ca, cb); !near(got, want, epsilon32) {
+			t.Fatalf("DotQuat(%v, %v) = %f, want %f", a, b, got, want)
+		}
+		if got, want := a.Inverted(), ca.Inverted(); !nearSlice(got[:], want[:], epsilon32) {
+			t.Fatalf("Inverted of %v = %v, want %v", a, got, want)
+		}
+		if quaternion.Dot(ca, cb) < 0.99 {
+			f := random[float32](r, 0, 1)
+			if got, want := Slerp(&a, &b, f), quaternion.Slerp(ca, cb, f); !nearSlice(got[:], want[:], epsilon32*10) {
+				t.Fatalf("Slerp(%v, %v, %f) = %v, want %v", a, b, f, got, want)
+			}
+		}
+
+		// RotatedVec3 keeps the length of v like the rotation matrix.
+		v := randomVec3[float32](r)
+		var m mat4x4.T
+		m.AssignQuaternion(ca)
+		if got, want := a.RotatedVec3(&v), m.MulVec3(ToVec3(&v)); !nearSlice(got[:], want[:], epsilon32*10) {
+			t.Fatalf("RotatedVec3(%v) of %v = %v, want %v", v, a, got, want)
+		}
+	}
+}
+
+func TestQuatFloat64(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for n := 0; n < 200; n++ {
+		axis := randomVec3[float64](r)
+		axis.Normalize()
+		angle := random[float64](r, -3, 3)
+		a := QuatFromAxisAngle(&axis, angle)
+		if want := quaterniond.FromAxisAngle(ToVec3d(&axis), angle); !nearSlice(a[:], want[:], epsilon64) {
+			t.Fatalf("QuatFromAxisAngle(%v, %f) = %v, want %v", axis, angle, a, want)
+		}
+		b := randomQuat[float64](r)
+		ca, cb := ToQuaterniond(&a), ToQuaterniond(&b)
+
+		if got, want := MulQuat(&a, &b), quaterniond.Mul(ca, cb); !nearSlice(got[:], want[:], epsilon64) {
+			t.Fatalf("MulQuat(%v, %v) = %v, want %v", a, b, got, want)
+		}
+		if got, want := DotQuat(&a, &b), quaterniond.Dot(ca, cb); !near(got, want, epsilon64) {
+			t.Fatalf("DotQuat(%v, %v) = %f, want %f", a, b, got, want)
+		}
+		if got, want := a.Inverted(), ca.Inverted(); !nearSlice(got[:], want[:], epsilon64) {
+			t.Fatalf("Inverted of %v = %v, want %v", a, got, want)
+		}
+		if quaterniond.Dot(ca, cb) < 0.99 {
+			f := random[float64](r, 0, 1)
+			if got, want := Slerp(&a, &b, f), quaterniond.Slerp(ca, cb, f); !nearSlice(got[:], want[:], epsilon64*10) {
+				t.Fatalf("Slerp(%v, %v, %f) = %v, want %v", a, b, f, got, want)
+			}
+		}
+
+		v := randomVec3[float64](r)
+		var m mat4x4d.T
+		m.AssignQuaternion(ca)
+		if got, want := a.RotatedVec3(&v), m.MulVec3(ToVec3d(&v)); !nearSlice(got[:], want[:], epsilon64*10) {
+			t.Fatalf("RotatedVec3(%v) of %v = %v, want %v", v, a, got, want)
+		}
+	}
+}
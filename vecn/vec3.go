@@ -0,0 +1,146 @@
+package vecn
+
+import (
+	"fmt"
+)
+
+// Vec3 is a generic 3D vector.
+type Vec3[F Float] [3]F
+
+// String formats Vec3 as string. See also ParseVec3().
+func (self *Vec3[F]) String() string {
+	return fmt.Sprintf("%f %f %f", self[0], self[1], self[2])
+}
+
+// ParseVec3 parses Vec3 from a string. See also String()
+func ParseVec3[F Float](s string) (r Vec3[F], err error) {
+	_, err = fmt.Sscanf(s, "%f %f %f", &r[0], &r[1], &r[2])
+	return r, err
+}
+
+// IsZero checks if all elements of the vector are zero.
+func (self *Vec3[F]) IsZero() bool {
+	return self[0] == 0 && self[1] == 0 && self[2] == 0
+}
+
+// Length returns the length of the vector.
+func (self *Vec3[F]) Length() F {
+	return sqrt(self.LengthSqr())
+}
+
+// LengthSqr returns the squared length of the vector.
+func (self *Vec3[F]) LengthSqr() F {
+	return self[0]*self[0] + self[1]*self[1] + self[2]*self[2]
+}
+
+// Scale multiplies all element of the vector by f and returns self.
+func (self *Vec3[F]) Scale(f F) *Vec3[F] {
+	self[0] *= f
+	self[1] *= f
+	self[2] *= f
+	return self
+}
+
+// Scaled returns a copy of self with all elements multiplies by f.
+func (self *Vec3[F]) Scaled(f F) Vec3[F] {
+	return Vec3[F]{self[0] * f, self[1] * f, self[2] * f}
+}
+
+func (self *Vec3[F]) Invert() *Vec3[F] {
+	self[0] = -self[0]
+	self[1] = -self[1]
+	self[2] = -self[2]
+	return self
+}
+
+func (self *Vec3[F]) Inverted() Vec3[F] {
+	return Vec3[F]{-self[0], -self[1], -self[2]}
+}
+
+func (self *Vec3[F]) Normalize() *Vec3[F] {
+	sl := self.LengthSqr()
+	if sl == 0 || sl == 1 {
+		return self
+	}
+	return self.Scale(1 / sqrt(sl))
+}
+
+func (self *Vec3[F]) Normalized() Vec3[F] {
+	v := *self
+	v.Normalize()
+	return v
+}
+
+func (self *Vec3[F]) Add(v *Vec3[F]) *Vec3[F] {
+	self[0] += v[0]
+	self[1] += v[1]
+	self[2] += v[2]
+	return self
+}
+
+func (self *Vec3[F]) Sub(v *Vec3[F]) *Vec3[F] {
+	self[0] -= v[0]
+	self[1] -= v[1]
+	self[2] -= v[2]
+	return self
+}
+
+func (self *Vec3[F]) Mul(v *Vec3[F]) *Vec3[F] {
+	self[0] *= v[0]
+	self[1] *= v[1]
+	self[2] *= v[2]
+	return self
+}
+
+// Vec4 returns the vector as homogeneous point with w set to 1.
+func (self *Vec3[F]) Vec4() Vec4[F] {
+	return Vec4[F]{self[0], self[1], self[2], 1}
+}
+
+func Add3[F Float](a, b *Vec3[F]) Vec3[F] {
+	return Vec3[F]{a[0] + b[0], a[1] + b[1], a[2] + b[2]}
+}
+
+func Sub3[F Float](a, b *Vec3[F]) Vec3[F] {
+	return Vec3[F]{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func Mul3[F Float](a, b *Vec3[F]) Vec3[F] {
+	return Vec3[F]{a[0] * b[0], a[1] * b[1], a[2] * b[2]}
+}
+
+func Dot3[F Float](a, b *Vec3[F]) F {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func Cross3[F Float](a, b *Vec3[F]) Vec3[F] {
+	return Vec3[F]{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func Angle3[F Float](a, b *Vec3[F]) F {
+	return acos(Dot3(a, b))
+}
+
+func Min3[F Float](a, b *Vec3[F]) Vec3[F] {
+	min := *a
+	for i := range min {
+		if b[i] < min[i] {
+			min[i] = b[i]
+		}
+	}
+	return min
+}
+
+func Max3[F Float](a, b *Vec3[F]) Vec3[F] {
+	max := *a
+	for i := range max {
+		if b[i] > max[i] {
+			max[i] = b[i]
+		}
+	}
+	return max
+}
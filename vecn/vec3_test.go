@@ -0,0 +1,74 @@
+//go:build go1.18
+// +build go1.18
+
+package vecn
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ungerik/go3d/vec3"
+	"github.com/ungerik/go3d/vec3d"
+)
+
+func TestVec3Float32(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for n := 0; n < 200; n++ {
+		a, b := randomVec3[float32](r), randomVec3[float32](r)
+		ca, cb := ToVec3(&a), ToVec3(&b)
+		if got, want := Cross3(&a, &b), vec3.Cross(ca, cb); !nearSlice(got[:], want[:], epsilon32) {
+			t.Fatalf("Cross3(%v, %v) = %v, want %v", a, b, got, want)
+		}
+		if got, want := Dot3(&a, &b), vec3.Dot(ca, cb); !near(got, want, epsilon32) {
+			t.Fatalf("Dot3(%v, %v) = %f, want %f", a, b, got, want)
+		}
+		// Angle3 like Angle expects normalized vectors.
+		na, nb := a.Normalized(), b.Normalized()
+		if got, want := Angle3(&na, &nb), vec3.Angle(ToVec3(&na), ToVec3(&nb)); !near(got, want, epsilon32*10) {
+			t.Fatalf("Angle3(%v, %v) = %f, want %f", a, b, got, want)
+		}
+		if got, want := Min3(&a, &b), vec3.Min(ca, cb); got != *FromVec3(&want) {
+			t.Fatalf("Min3(%v, %v) = %v, want %v", a, b, got, want)
+		}
+		if got, want := Max3(&a, &b), vec3.Max(ca, cb); got != *FromVec3(&want) {
+			t.Fatalf("Max3(%v, %v) = %v, want %v", a, b, got, want)
+		}
+		if got, want := a.Length(), ca.Length(); !near(got, want, epsilon32) {
+			t.Fatalf("Length of %v = %f, want %f", a, got, want)
+		}
+		if got, want := a.Normalized(), ca.Normalized(); !nearSlice(got[:], want[:], epsilon32) {
+			t.Fatalf("Normalized of %v = %v, want %v", a, got, want)
+		}
+	}
+}
+
+func TestVec3Float64(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for n := 0; n < 200; n++ {
+		a, b := randomVec3[float64](r), randomVec3[float64](r)
+		ca, cb := ToVec3d(&a), ToVec3d(&b)
+		if got, want := Cross3(&a, &b), vec3d.Cross(ca, cb); !nearSlice(got[:], want[:], epsilon64) {
+			t.Fatalf("Cross3(%v, %v) = %v, want %v", a, b, got, want)
+		}
+		if got, want := Dot3(&a, &b), vec3d.Dot(ca, cb); !near(got, want, epsilon64) {
+			t.Fatalf("Dot3(%v, %v) = %f, want %f", a, b, got, want)
+		}
+		// Angle3 like Angle expects normalized vectors.
+		na, nb := a.Normalized(), b.Normalized()
+		if got, want := Angle3(&na, &nb), vec3d.Angle(ToVec3d(&na), ToVec3d(&nb)); !near(got, want, epsilon64*10) {
+			t.Fatalf("Angle3(%v, %v) = %f, want %f", a, b, got, want)
+		}
+		if got, want := Min3(&a, &b), vec3d.Min(ca, cb); got != *FromVec3d(&want) {
+			t.Fatalf("Min3(%v, %v) = %v, want %v", a, b, got, want)
+		}
+		if got, want := Max3(&a, &b), vec3d.Max(ca, cb); got != *FromVec3d(&want) {
+			t.Fatalf("Max3(%v, %v) = %v, want %v", a, b, got, want)
+		}
+		if got, want := a.Length(), ca.Length(); !near(got, want, epsilon64) {
+			t.Fatalf("Length of %v = %f, want %f", a, got, want)
+		}
+		if got, want := a.Normalized(), ca.Normalized(); !nearSlice(got[:], want[:], epsilon64) {
+			t.Fatalf("Normalized of %v = %v, want %v", a, got, want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package vecn
+
+import (
+	"fmt"
+)
+
+// Vec4 is a generic 4D vector or homogeneous 3D point.
+type Vec4[F Float] [4]F
+
+// String formats Vec4 as string. See also ParseVec4().
+func (self *Vec4[F]) String() string {
+	return fmt.Sprintf("%f %f %f %f", self[0], self[1], self[2], self[3])
+}
+
+// ParseVec4 parses Vec4 from a string. See also String()
+func ParseVec4[F Float](s string) (r Vec4[F], err error) {
+	_, err = fmt.Sscanf(s, "%f %f %f %f", &r[0], &r[1], &r[2], &r[3])
+	return r, err
+}
+
+// IsZero checks if all elements of the vector are zero.
+func (self *Vec4[F]) IsZero() bool {
+	return self[0] == 0 && self[1] == 0 && self[2] == 0 && self[3] == 0
+}
+
+// Vec3 returns the first three elements of the vector.
+func (self *Vec4[F]) Vec3() Vec3[F] {
+	return Vec3[F]{self[0], self[1], self[2]}
+}
+
+// Vec3DividedByW returns the first three elements divided by the fourth.
+func (self *Vec4[F]) Vec3DividedByW() Vec3[F] {
+	oow := 1 / self[3]
+	return Vec3[F]{self[0] * oow, self[1] * oow, self[2] * oow}
+}
+
+func Dot4[F Float](a, b *Vec4[F]) F {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] + a[3]*b[3]
+}
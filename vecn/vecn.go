@@ -0,0 +1,53 @@
+// The package vecn contains generic versions of the vector, matrix
+// and quaternion types of go3d that work with float32 and float64.
+//
+// Code that doesn't depend on the precision can be written once
+// against Vec3[F], Vec4[F], Mat4[F] and Quat[F]. The types have the same
+// memory layout as their concrete counterparts, so the conversion functions
+// like FromVec3 and ToMat4x4d only reinterpret pointers and don't copy.
+// The concrete packages remain the better choice for hot paths.
+//
+// The generic types only cover a part of the concrete packages.
+// Mat4 has no look-at or projection builders, for example.
+// Build such matrices with mat4x4 or mat4x4d and convert them
+// with FromMat4x4 or FromMat4x4d.
+//
+// The package needs Go 1.18 or later for generics, so the go3d root
+// package imports it only behind a build constraint.
+package vecn
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrSingular is returned when inverting a singular matrix.
+var ErrSingular = errors.New("vecn: matrix is singular")
+
+// Float is the constraint for the element types of the generic types.
+type Float interface {
+	~float32 | ~float64
+}
+
+func sqrt[F Float](x F) F {
+	return F(math.Sqrt(float64(x)))
+}
+
+func sin[F Float](x F) F {
+	return F(math.Sin(float64(x)))
+}
+
+func cos[F Float](x F) F {
+	return F(math.Cos(float64(x)))
+}
+
+func acos[F Float](x F) F {
+	return F(math.Acos(float64(x)))
+}
+
+func abs[F Float](x F) F {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
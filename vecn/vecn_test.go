@@ -0,0 +1,155 @@
+//go:build go1.18
+// +build go1.18
+
+package vecn
+
+import (
+	"math/rand"
+	"testing"
+	"unsafe"
+
+	"github.com/ungerik/go3d/mat4x4"
+	"github.com/ungerik/go3d/mat4x4d"
+	"github.com/ungerik/go3d/quaternion"
+	"github.com/ungerik/go3d/quaterniond"
+	"github.com/ungerik/go3d/vec3"
+	"github.com/ungerik/go3d/vec3d"
+	"github.com/ungerik/go3d/vec4"
+	"github.com/ungerik/go3d/vec4d"
+)
+
+// The relative tolerances of comparisons with the concrete types.
+const (
+	epsilon32 = 1e-5
+	epsilon64 = 1e-12
+)
+
+func near[F Float](a, b, epsilon F) bool {
+	return abs(a-b) <= epsilon*(1+abs(a)+abs(b))
+}
+
+func nearSlice[F Float](a, b []F, epsilon F) bool {
+	for i := range a {
+		if !near(a[i], b[i], epsilon) {
+			return false
+		}
+	}
+	return true
+}
+
+func nearMat4[F Float](a, b *Mat4[F], epsilon F) bool {
+	for col := range a {
+		if !nearSlice(a[col][:], b[col][:], epsilon) {
+			return false
+		}
+	}
+	return true
+}
+
+func random[F Float](r *rand.Rand, min, max F) F {
+	return min + F(r.Float64())*(max-min)
+}
+
+func randomVec3[F Float](r *rand.Rand) Vec3[F] {
+	return Vec3[F]{random[F](r, -2, 2), random[F](r, -2, 2), random[F](r, -2, 2)}
+}
+
+func randomMat4[F Float](r *rand.Rand) Mat4[F] {
+	var m Mat4[F]
+	for col := range m {
+		for row := range m[col] {
+			m[col][row] = random[F](r, -2, 2)
+		}
+	}
+	return m
+}
+
+func randomQuat[F Float](r *rand.Rand) Quat[F] {
+	axis := randomVec3[F](r)
+	for axis.LengthSqr() == 0 {
+		axis = randomVec3[F](r)
+	}
+	axis.Normalize()
+	return QuatFromAxisAngle(&axis, random[F](r, -3, 3))
+}
+
+func TestSizes(t *testing.T) {
+	sizes := []struct {
+		name           string
+		generic, fixed uintptr
+	}{
+		{"Vec3[float32]", unsafe.Sizeof(Vec3[float32]{}), unsafe.Sizeof(vec3.T{})},
+		{"Vec3[float64]", unsafe.Sizeof(Vec3[float64]{}), unsafe.Sizeof(vec3d.T{})},
+		{"Vec4[float32]", unsafe.Sizeof(Vec4[float32]{}), unsafe.Sizeof(vec4.T{})},
+		{"Vec4[float64]", unsafe.Sizeof(Vec4[float64]{}), unsafe.Sizeof(vec4d.T{})},
+		{"Mat4[float32]", unsafe.Sizeof(Mat4[float32]{}), unsafe.Sizeof(mat4x4.T{})},
+		{"Mat4[float64]", unsafe.Sizeof(Mat4[float64]{}), unsafe.Sizeof(mat4x4d.T{})},
+		{"Quat[float32]", unsafe.Sizeof(Quat[float32]{}), unsafe.Sizeof(quaternion.T{})},
+		{"Quat[float64]", unsafe.Sizeof(Quat[float64]{}), unsafe.Sizeof(quaterniond.T{})},
+	}
+	for _, s := range sizes {
+		if s.generic != s.fixed {
+			t.Errorf("%s has %d bytes, its concrete twin %d", s.name, s.generic, s.fixed)
+		}
+	}
+}
+
+func TestConversions(t *testing.T) {
+	v3 := vec3.T{1, 2, 3}
+	if g := FromVec3(&v3); ToVec3(g) != &v3 || *g != (Vec3[float32]{1, 2, 3}) {
+		t.Errorf("FromVec3(%v) = %v", v3, g)
+	}
+	FromVec3(&v3)[1] = 5
+	v3d := vec3d.T{1, 2, 3}
+	if g := FromVec3d(&v3d); ToVec3d(g) != &v3d || *g != (Vec3[float64]{1, 2, 3}) {
+		t.Errorf("FromVec3d(%v) = %v", v3d, g)
+	}
+	FromVec3d(&v3d)[1] = 5
+	if v3[1] != 5 || v3d[1] != 5 {
+		t.Errorf("FromVec3 and FromVec3d don't share the memory of their arguments")
+	}
+
+	v4 := vec4.T{1, 2, 3, 4}
+	if g := FromVec4(&v4); ToVec4(g) != &v4 || *g != (Vec4[float32]{1, 2, 3, 4}) {
+		t.Errorf("FromVec4(%v) = %v", v4, g)
+	}
+	v4d := vec4d.T{1, 2, 3, 4}
+	if g := FromVec4d(&v4d); ToVec4d(g) != &v4d || *g != (Vec4[float64]{1, 2, 3, 4}) {
+		t.Errorf("FromVec4d(%v) = %v", v4d, g)
+	}
+
+	q := quaternion.T{1, 2, 3, 4}
+	if g := FromQuaternion(&q); ToQuaternion(g) != &q || *g != (Quat[float32]{1, 2, 3, 4}) {
+		t.Errorf("FromQuaternion(%v) = %v", q, g)
+	}
+	qd := quaterniond.T{1, 2, 3, 4}
+	if g := FromQuaterniond(&qd); ToQuaterniond(g) != &qd || *g != (Quat[float64]{1, 2, 3, 4}) {
+		t.Errorf("FromQuaterniond(%v) = %v", qd, g)
+	}
+
+	// The matrix conversions use unsafe, so check every element.
+	var m mat4x4.T
+	var md mat4x4d.T
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			m[col][row] = float32(col*4 + row)
+			md[col][row] = float64(col*4 + row)
+		}
+	}
+	g, gd := FromMat4x4(&m), FromMat4x4d(&md)
+	if ToMat4x4(g) != &m || ToMat4x4d(gd) != &md {
+		t.Errorf("the matrix conversions don't round-trip")
+	}
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			if g[col][row] != m[col][row] || gd[col][row] != md[col][row] {
+				t.Fatalf("FromMat4x4 and FromMat4x4d differ at column %d row %d", col, row)
+			}
+		}
+	}
+	g[2][1] = -1
+	gd[2][1] = -1
+	if m[2][1] != -1 || md[2][1] != -1 {
+		t.Errorf("FromMat4x4 and FromMat4x4d don't share the memory of their arguments")
+	}
+}